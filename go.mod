@@ -5,4 +5,6 @@ go 1.16
 require (
 	github.com/hashicorp/terraform-plugin-sdk v1.17.2
 	github.com/leidruid/go-teamcity v1.3.1
+	golang.org/x/crypto v0.0.0-20210322153248-0c34fe9e7dc2
+	golang.org/x/net v0.0.0-20210326060303-6b1517762897
 )
@@ -0,0 +1,830 @@
+package teamcity
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/mutexkv"
+	api "github.com/leidruid/go-teamcity/teamcity"
+)
+
+// buildStep is a build step as sent to and returned by the buildTypes/{id}/steps endpoint.
+// go-teamcity only models a few runner types and drops steps of other types when reading them,
+// so steps are read and written with raw REST calls. Steps of the runner types go-teamcity models are
+// converted with the MarshalJSON and UnmarshalJSON methods of their go-teamcity type.
+type buildStep struct {
+	ID         string          `json:"id,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	Type       string          `json:"type"`
+	Disabled   bool            `json:"disabled,omitempty"`
+	Inherited  bool            `json:"inherited,omitempty"`
+	Properties *api.Properties `json:"properties,omitempty"`
+}
+
+type buildSteps struct {
+	Items []*buildStep `json:"step"`
+}
+
+// Runner types of the build steps the provider models in addition to the go-teamcity ones
+const (
+	stepTypeMaven  = "Maven2"
+	stepTypeDotnet = "dotnet"
+	stepTypePython = "python-runner"
+	stepTypeNodeJS = "nodejs-runner"
+	stepTypeKotlin = "kotlinScript"
+
+	stepTypeSSHExec   = "ssh-exec-runner"
+	stepTypeSSHUpload = "ssh-deploy-runner"
+)
+
+// Positions of the steps managed by teamcity_build_step resources among the steps of their build configuration
+const (
+	buildStepPositionFirst = "first"
+	buildStepPositionLast  = "last"
+)
+
+// buildStepsMutexKV serializes the changes to the steps of a build configuration, which resources reorder by replacing them all
+var buildStepsMutexKV = mutexkv.NewMutexKV()
+
+func (c *Client) getBuildSteps(buildTypeID string) ([]*buildStep, error) {
+	var out buildSteps
+	if err := c.doRequest("GET", buildTypePath(buildTypeID)+"/steps", nil, &out, "build steps"); err != nil {
+		return nil, err
+	}
+	return out.Items, nil
+}
+
+// getOwnBuildSteps returns the steps of a build configuration, without the ones inherited from its templates.
+// Inherited steps are managed with the template: sending them back would turn them into overrides, detaching them from it.
+func (c *Client) getOwnBuildSteps(buildTypeID string) ([]*buildStep, error) {
+	steps, err := c.getBuildSteps(buildTypeID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*buildStep, 0, len(steps))
+	for _, s := range steps {
+		if !s.Inherited {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (c *Client) addBuildStep(buildTypeID string, dt *buildStep) (*buildStep, error) {
+	var out buildStep
+	if err := c.doRequest("POST", buildTypePath(buildTypeID)+"/steps", dt, &out, "build step"); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) getBuildStep(buildTypeID string, id string) (*buildStep, error) {
+	var out buildStep
+	if err := c.doRequest("GET", buildTypeItemPath(buildTypeID, "steps", id), nil, &out, "build step"); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) updateBuildStep(buildTypeID string, dt *buildStep) error {
+	return c.doRequest("PUT", buildTypeItemPath(buildTypeID, "steps", dt.ID), dt, nil, "build step")
+}
+
+// replaceBuildSteps replaces the steps of a build configuration. Steps sent with an ID keep it.
+func (c *Client) replaceBuildSteps(buildTypeID string, steps []*buildStep) error {
+	return c.doRequest("PUT", buildTypePath(buildTypeID)+"/steps", &buildSteps{Items: steps}, nil, "build steps")
+}
+
+// updateBuildSteps applies the changes of the step list of a build configuration: steps are matched to the steps in state
+// by name and runner type, or by position when renamed. Matched steps keep their ID and are only updated when changed,
+// unmatched steps are removed or added, and the steps are reordered only when their order changed.
+// The steps not in state, managed by teamcity_build_step resources or outside Terraform, are left untouched apart from
+// being kept around the list, and so are the steps inherited from templates.
+// The steps of the new list are returned with the IDs they were given, even on error.
+func updateBuildSteps(client *Client, buildTypeID string, o []interface{}, n []interface{}) ([]*buildStep, error) {
+	buildStepsMutexKV.Lock(buildTypeID)
+	defer buildStepsMutexKV.Unlock(buildTypeID)
+
+	old, err := expandBuildSteps(o)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := expandBuildSteps(n)
+	if err != nil {
+		return nil, err
+	}
+	current, err := client.getBuildSteps(buildTypeID)
+	if err != nil {
+		return nil, err
+	}
+	inherited := make(map[string]bool)
+	for _, s := range current {
+		if s.Inherited {
+			inherited[s.ID] = true
+		}
+	}
+
+	// The step IDs of the new list come from the state entries at the same position, they are set again below.
+	// Inherited steps held by state written by earlier versions are neither matched nor removed.
+	matched := make([]int, len(steps))
+	used := make([]bool, len(old))
+	for j, os := range old {
+		used[j] = inherited[os.ID]
+	}
+	for i, s := range steps {
+		s.ID = ""
+		matched[i] = -1
+		if s.Name == "" {
+			continue
+		}
+		for j, os := range old {
+			if !used[j] && os.Name == s.Name && os.Type == s.Type {
+				matched[i], used[j] = j, true
+				break
+			}
+		}
+	}
+	for i, s := range steps {
+		if matched[i] < 0 && i < len(old) && !used[i] && old[i].Type == s.Type {
+			matched[i], used[i] = i, true
+		}
+	}
+
+	for j, os := range old {
+		if !used[j] && os.ID != "" {
+			if err := deleteStep(client, buildTypeID, os.ID); err != nil && !isNotFound(err) {
+				return steps, err
+			}
+		}
+	}
+
+	for i, s := range steps {
+		j := matched[i]
+		if j < 0 {
+			created, err := client.addBuildStep(buildTypeID, s)
+			if err != nil {
+				return steps, err
+			}
+			s.ID = created.ID
+			continue
+		}
+
+		s.ID = old[j].ID
+		if !buildStepsEqual(s, old[j]) {
+			if err := client.updateBuildStep(buildTypeID, s); err != nil {
+				return steps, err
+			}
+		}
+	}
+
+	return steps, client.orderBuildSteps(buildTypeID, steps)
+}
+
+// buildStepsState returns the step list to keep in the state of a build configuration after updateBuildSteps:
+// the new list with the IDs of the steps. The step block only manages the steps whose ID is in state, so when
+// the update failed the old list is kept as well, as any of its steps can still exist. Refreshing drops the ones that don't.
+func buildStepsState(o []interface{}, n []interface{}, steps []*buildStep, failed bool) []interface{} {
+	out := make([]interface{}, 0, len(n))
+	ids := make(map[string]bool)
+	for i, raw := range n {
+		if i >= len(steps) || steps[i].ID == "" {
+			continue
+		}
+		m := make(map[string]interface{})
+		for k, v := range raw.(map[string]interface{}) {
+			m[k] = v
+		}
+		m["step_id"] = steps[i].ID
+		ids[steps[i].ID] = true
+		out = append(out, m)
+	}
+	if !failed {
+		return out
+	}
+	for _, raw := range o {
+		if id, _ := raw.(map[string]interface{})["step_id"].(string); id != "" && !ids[id] {
+			out = append(out, raw)
+		}
+	}
+	return out
+}
+
+// orderBuildSteps puts the steps of the step block of a build configuration in the order of blockSteps. The other steps,
+// managed by teamcity_build_step resources or outside Terraform, are kept before the block when they currently precede
+// all of its steps, and after it otherwise. Steps inherited from templates are not part of the reordered steps.
+func (c *Client) orderBuildSteps(buildTypeID string, blockSteps []*buildStep) error {
+	current, err := c.getOwnBuildSteps(buildTypeID)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]*buildStep)
+	for _, s := range blockSteps {
+		known[s.ID] = s
+	}
+
+	var before, after []*buildStep
+	inBlock := false
+	for _, s := range current {
+		switch _, ok := known[s.ID]; {
+		case ok:
+			inBlock = true
+		case inBlock:
+			after = append(after, s)
+		default:
+			before = append(before, s)
+		}
+	}
+
+	steps := make([]*buildStep, 0, len(current))
+	steps = append(append(append(steps, before...), blockSteps...), after...)
+	return c.reorderBuildSteps(buildTypeID, current, steps, known)
+}
+
+// moveBuildStep puts a step managed by a teamcity_build_step resource first or last of the steps of its build configuration
+func (c *Client) moveBuildStep(buildTypeID string, own *buildStep, position string) error {
+	current, err := c.getOwnBuildSteps(buildTypeID)
+	if err != nil {
+		return err
+	}
+
+	steps := make([]*buildStep, 0, len(current))
+	if position == buildStepPositionFirst {
+		steps = append(steps, own)
+	}
+	for _, s := range current {
+		if s.ID != own.ID {
+			steps = append(steps, s)
+		}
+	}
+	if position != buildStepPositionFirst {
+		steps = append(steps, own)
+	}
+	return c.reorderBuildSteps(buildTypeID, current, steps, map[string]*buildStep{own.ID: own})
+}
+
+// reorderBuildSteps replaces the steps of a build configuration to put them in the order of steps, unless they already are.
+// TeamCity can only reorder steps by replacing all of them, so the steps not in known are sent back as read,
+// which fails when TeamCity did not return the value of one of their secure properties.
+func (c *Client) reorderBuildSteps(buildTypeID string, current []*buildStep, steps []*buildStep, known map[string]*buildStep) error {
+	if sameBuildStepOrder(current, steps) {
+		return nil
+	}
+	for _, s := range steps {
+		if _, ok := known[s.ID]; ok {
+			continue
+		}
+		if name := hiddenSecureProperty(s); name != "" {
+			return fmt.Errorf("cannot reorder the steps of build configuration '%s': TeamCity does not return the value of property '%s' of step '%s', which would be lost. Reference a password parameter in the step instead", buildTypeID, name, s.ID)
+		}
+	}
+	return c.replaceBuildSteps(buildTypeID, steps)
+}
+
+func sameBuildStepOrder(a []*buildStep, b []*buildStep) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ID != b[i].ID {
+			return false
+		}
+	}
+	return true
+}
+
+// hiddenSecureProperty returns the name of a secure property of the step whose value TeamCity did not return, if any
+func hiddenSecureProperty(s *buildStep) string {
+	if s.Properties == nil {
+		return ""
+	}
+	for _, p := range s.Properties.Items {
+		if strings.HasPrefix(p.Name, "secure:") && p.Value == "" {
+			return p.Name
+		}
+	}
+	return ""
+}
+
+// buildStepsEqual reports whether two steps have the same settings, ignoring their ID
+func buildStepsEqual(a *buildStep, b *buildStep) bool {
+	if a.Name != b.Name || a.Type != b.Type || a.Disabled != b.Disabled {
+		return false
+	}
+	var pa, pb map[string]string
+	if a.Properties != nil {
+		pa = a.Properties.Map()
+	}
+	if b.Properties != nil {
+		pb = b.Properties.Map()
+	}
+	return reflect.DeepEqual(pa, pb)
+}
+
+// newBuildStep converts a step of a runner type modelled by go-teamcity
+func newBuildStep(s api.Step) (*buildStep, error) {
+	dt, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	var out buildStep
+	if err := json.Unmarshal(dt, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// decode converts the step into out, a go-teamcity step of the same runner type
+func (s *buildStep) decode(out json.Unmarshaler) error {
+	dt, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return out.UnmarshalJSON(dt)
+}
+
+// property returns the value of a property of the step, or an empty string if it is not set
+func (s *buildStep) property(name string) string {
+	if s.Properties == nil {
+		return ""
+	}
+	v, _ := s.Properties.GetOk(name)
+	return v
+}
+
+// newRunnerStep starts a step of a runner type not modelled by go-teamcity, with the settings shared by all step types
+func newRunnerStep(stepType string, dt map[string]interface{}) *buildStep {
+	s := &buildStep{Type: stepType, Properties: api.NewPropertiesEmpty()}
+	if v, ok := dt["step_id"]; ok {
+		s.ID = v.(string)
+	}
+	if v, ok := dt["name"]; ok {
+		s.Name = v.(string)
+	}
+	if v, ok := dt["execute_step"]; ok {
+		setStepProperty(s, "teamcity.step.mode", v.(string))
+	}
+	if v, ok := dt["execute_conditions"]; ok {
+		if ecs := expandStringMapConditions(v.([]interface{})); len(ecs) > 0 {
+			raw, _ := json.Marshal(ecs)
+			setStepProperty(s, "teamcity.step.conditions", string(raw))
+		}
+	}
+	if v, ok := dt["work_dir"]; ok {
+		setStepProperty(s, "teamcity.build.workingDir", v.(string))
+	}
+	return s
+}
+
+// flattenRunnerStep returns the settings shared by all step types, of a step of a runner type not modelled by go-teamcity
+func flattenRunnerStep(s *buildStep, mapType string) map[string]interface{} {
+	m := make(map[string]interface{})
+	if s.Name != "" {
+		m["name"] = s.Name
+	}
+	if v := s.property("teamcity.step.mode"); v != "" {
+		m["execute_step"] = v
+	}
+	if v := s.property("teamcity.step.conditions"); v != "" {
+		var ecs [][]string
+		if err := json.Unmarshal([]byte(v), &ecs); err == nil && len(ecs) > 0 {
+			m["execute_conditions"] = flattenExecuteConditions(ecs)
+		}
+	}
+	if v := s.property("teamcity.build.workingDir"); v != "" {
+		m["work_dir"] = v
+	}
+	m["type"] = mapType
+	return m
+}
+
+// setStepProperty adds a property to the step, unless its value is empty
+func setStepProperty(s *buildStep, name string, value string) {
+	if value != "" {
+		s.Properties.AddOrReplaceValue(name, value)
+	}
+}
+
+func stepString(dt map[string]interface{}, key string) string {
+	if v, ok := dt[key]; ok && v != nil {
+		return v.(string)
+	}
+	return ""
+}
+
+func stepStringList(dt map[string]interface{}, key string) []string {
+	v, ok := dt[key]
+	if !ok || v == nil {
+		return nil
+	}
+	var out []string
+	for _, e := range v.([]interface{}) {
+		out = append(out, e.(string))
+	}
+	return out
+}
+
+// splitStepLines splits a multi-line property, e.g. the list of projects of a .NET step
+func splitStepLines(v string) []string {
+	var out []string
+	for _, l := range strings.Split(v, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// mavenPathRegexp matches the Maven installations provided as TeamCity tools, e.g. %teamcity.tool.maven.3.6.3%
+var mavenPathRegexp = regexp.MustCompile(`^%teamcity\.tool\.maven\.(.+)%$`)
+
+func expandStepMaven(dt map[string]interface{}) (*buildStep, error) {
+	goals := stepString(dt, "goals")
+	if goals == "" {
+		return nil, fmt.Errorf("'goals' is required for maven steps")
+	}
+
+	s := newRunnerStep(stepTypeMaven, dt)
+	setStepProperty(s, "goals", goals)
+	setStepProperty(s, "pomLocation", stepString(dt, "file"))
+	setStepProperty(s, "runnerArgs", stepString(dt, "args"))
+	setStepProperty(s, "jvmArgs", stepString(dt, "jvm_args"))
+	setStepProperty(s, "target.jdk.home", stepString(dt, "jdk_home"))
+	if v := stepString(dt, "maven_version"); v != "" {
+		setStepProperty(s, "maven.path", fmt.Sprintf("%%teamcity.tool.maven.%s%%", v))
+	}
+	return s, nil
+}
+
+func flattenBuildStepMaven(s *buildStep) map[string]interface{} {
+	m := flattenRunnerStep(s, "maven")
+	m["goals"] = s.property("goals")
+	m["file"] = s.property("pomLocation")
+	m["args"] = s.property("runnerArgs")
+	m["jvm_args"] = s.property("jvmArgs")
+	m["jdk_home"] = s.property("target.jdk.home")
+	// The default Maven installation is not configured explicitly
+	if v := mavenPathRegexp.FindStringSubmatch(s.property("maven.path")); v != nil && v[1] != "DEFAULT" {
+		m["maven_version"] = v[1]
+	}
+	return m
+}
+
+// dotnetCommandFields lists the arguments only supported by some commands of .NET steps
+var dotnetCommandFields = map[string][]string{
+	"configuration":  {"build", "test", "publish", "pack"},
+	"framework":      {"build", "test", "publish"},
+	"runtime":        {"build", "publish"},
+	"output_dir":     {"build", "publish", "pack"},
+	"version_suffix": {"build", "publish", "pack"},
+	"skip_build":     {"test", "pack"},
+	"test_filter":    {"test"},
+	"nuget_source":   {"nuget-push"},
+	"nuget_api_key":  {"nuget-push"},
+}
+
+// dotnetSecureProperties maps the arguments of .NET steps TeamCity never returns to their property
+var dotnetSecureProperties = map[string]string{
+	"nuget_api_key": "secure:nuget.apiKey",
+}
+
+func expandStepDotnet(dt map[string]interface{}) (*buildStep, error) {
+	command := stepString(dt, "command")
+	if command == "" {
+		return nil, fmt.Errorf("'command' is required for dotnet steps")
+	}
+	for field, commands := range dotnetCommandFields {
+		set := false
+		switch v := dt[field].(type) {
+		case string:
+			set = v != ""
+		case bool:
+			set = v
+		}
+		supported := false
+		for _, c := range commands {
+			supported = supported || c == command
+		}
+		if set && !supported {
+			return nil, fmt.Errorf("'%s' cannot be set with dotnet command '%s'", field, command)
+		}
+	}
+
+	s := newRunnerStep(stepTypeDotnet, dt)
+	setStepProperty(s, "command", command)
+	setStepProperty(s, "paths", strings.Join(stepStringList(dt, "projects"), "\n"))
+	setStepProperty(s, "configuration", stepString(dt, "configuration"))
+	setStepProperty(s, "framework", stepString(dt, "framework"))
+	setStepProperty(s, "runtime", stepString(dt, "runtime"))
+	setStepProperty(s, "outputDir", stepString(dt, "output_dir"))
+	setStepProperty(s, "versionSuffix", stepString(dt, "version_suffix"))
+	setStepProperty(s, "test.testCaseFilter", stepString(dt, "test_filter"))
+	setStepProperty(s, "nuget.packageSource", stepString(dt, "nuget_source"))
+	setStepProperty(s, dotnetSecureProperties["nuget_api_key"], stepString(dt, "nuget_api_key"))
+	setStepProperty(s, "args", stepString(dt, "args"))
+	if v, ok := dt["skip_build"]; ok && v.(bool) {
+		setStepProperty(s, "skipBuild", "true")
+	}
+	return s, nil
+}
+
+func flattenBuildStepDotnet(s *buildStep) map[string]interface{} {
+	m := flattenRunnerStep(s, "dotnet")
+	m["command"] = s.property("command")
+	m["projects"] = splitStepLines(s.property("paths"))
+	m["configuration"] = s.property("configuration")
+	m["framework"] = s.property("framework")
+	m["runtime"] = s.property("runtime")
+	m["output_dir"] = s.property("outputDir")
+	m["version_suffix"] = s.property("versionSuffix")
+	m["test_filter"] = s.property("test.testCaseFilter")
+	m["nuget_source"] = s.property("nuget.packageSource")
+	m["args"] = s.property("args")
+	m["skip_build"] = s.property("skipBuild") == "true"
+	return m
+}
+
+// pythonKinds maps the arguments selecting what a Python step runs to the python-kind property
+var pythonKinds = map[string]string{
+	"file":   "1",
+	"module": "2",
+	"code":   "3",
+}
+
+var pythonKindProperties = map[string]string{
+	"file":   "python-script-file-name",
+	"module": "python-module-name",
+	"code":   "python-script-code",
+}
+
+func expandStepPython(dt map[string]interface{}) (*buildStep, error) {
+	var kind string
+	for field := range pythonKinds {
+		if stepString(dt, field) == "" {
+			continue
+		}
+		if kind != "" {
+			return nil, fmt.Errorf("only one of 'file', 'module' or 'code' can be set for python steps")
+		}
+		kind = field
+	}
+	if kind == "" {
+		return nil, fmt.Errorf("one of 'file', 'module' or 'code' is required for python steps")
+	}
+
+	s := newRunnerStep(stepTypePython, dt)
+	setStepProperty(s, "python-kind", pythonKinds[kind])
+	setStepProperty(s, pythonKindProperties[kind], stepString(dt, kind))
+	setStepProperty(s, "python-script-args", stepString(dt, "args"))
+	setStepProperty(s, "python-exe", stepString(dt, "python_executable"))
+	return s, nil
+}
+
+func flattenBuildStepPython(s *buildStep) map[string]interface{} {
+	m := flattenRunnerStep(s, "python")
+	for field, kind := range pythonKinds {
+		if s.property("python-kind") == kind {
+			m[field] = s.property(pythonKindProperties[field])
+		}
+	}
+	m["args"] = s.property("python-script-args")
+	m["python_executable"] = s.property("python-exe")
+	return m
+}
+
+func expandStepNodeJS(dt map[string]interface{}) (*buildStep, error) {
+	commands := stepStringList(dt, "commands")
+	if len(commands) == 0 {
+		return nil, fmt.Errorf("'commands' is required for nodejs steps")
+	}
+
+	s := newRunnerStep(stepTypeNodeJS, dt)
+	setStepProperty(s, "shellScript", strings.Join(commands, "\n"))
+	return s, nil
+}
+
+func flattenBuildStepNodeJS(s *buildStep) map[string]interface{} {
+	m := flattenRunnerStep(s, "nodejs")
+	m["commands"] = splitStepLines(s.property("shellScript"))
+	return m
+}
+
+var kotlinPathRegexp = regexp.MustCompile(`^%teamcity\.tool\.kotlin\.compiler\.(.+)%$`)
+
+func expandStepKotlin(dt map[string]interface{}) (*buildStep, error) {
+	file, code := stepString(dt, "file"), stepString(dt, "code")
+	if (file == "") == (code == "") {
+		return nil, fmt.Errorf("one of 'file' or 'code' is required for kotlin_script steps")
+	}
+
+	s := newRunnerStep(stepTypeKotlin, dt)
+	if file != "" {
+		setStepProperty(s, "scriptType", "file")
+		setStepProperty(s, "scriptFile", file)
+	} else {
+		setStepProperty(s, "scriptType", "customScript")
+		setStepProperty(s, "scriptContent", code)
+	}
+	setStepProperty(s, "kotlinArgs", stepString(dt, "args"))
+	setStepProperty(s, "jvmArgs", stepString(dt, "jvm_args"))
+	setStepProperty(s, "target.jdk.home", stepString(dt, "jdk_home"))
+	// TeamCity requires the compiler to be set, the default one is used unless a version is given
+	version := stepString(dt, "kotlin_version")
+	if version == "" {
+		version = "DEFAULT"
+	}
+	setStepProperty(s, "kotlinPath", fmt.Sprintf("%%teamcity.tool.kotlin.compiler.%s%%", version))
+	return s, nil
+}
+
+func flattenBuildStepKotlin(s *buildStep) map[string]interface{} {
+	m := flattenRunnerStep(s, "kotlin_script")
+	if s.property("scriptType") == "file" {
+		m["file"] = s.property("scriptFile")
+	} else {
+		m["code"] = s.property("scriptContent")
+	}
+	m["args"] = s.property("kotlinArgs")
+	m["jvm_args"] = s.property("jvmArgs")
+	m["jdk_home"] = s.property("target.jdk.home")
+	if v := kotlinPathRegexp.FindStringSubmatch(s.property("kotlinPath")); v != nil && v[1] != "DEFAULT" {
+		m["kotlin_version"] = v[1]
+	}
+	return m
+}
+
+// Properties of the SSH Exec and SSH Upload runners, provided by the TeamCity deployer plugin
+const (
+	sshTargetProperty     = "jetbrains.buildServer.deployer.targetUrl"
+	sshPortProperty       = "jetbrains.buildServer.sshexec.port"
+	sshUsernameProperty   = "jetbrains.buildServer.deployer.username"
+	sshAuthMethodProperty = "jetbrains.buildServer.sshexec.authMethod"
+	sshKeyProperty        = "teamcitySshKey"
+	sshTimeoutProperty    = "jetbrains.buildServer.sshexec.timeout"
+	sshCommandsProperty   = "jetbrains.buildServer.sshexec.command"
+	sshSourcePathProperty = "jetbrains.buildServer.deployer.sourcePath"
+	sshTransportProperty  = "jetbrains.buildServer.deployer.ssh.transport"
+)
+
+// sshAuthMethods maps the auth_method argument of SSH steps to the authentication methods of the runners
+var sshAuthMethods = map[string]string{
+	"uploaded_key": "UPLOADED_KEY",
+	"password":     "PWD",
+}
+
+// sshSecureProperties maps the arguments of SSH steps TeamCity never returns to their property.
+// The password is also the passphrase of uploaded keys.
+var sshSecureProperties = map[string]string{
+	"password": "secure:jetbrains.buildServer.deployer.password",
+}
+
+// expandStepSSH builds an SSH Exec step running commands, or an SSH Upload step when upload is true
+func expandStepSSH(dt map[string]interface{}, upload bool) (*buildStep, error) {
+	stepType, mapType := stepTypeSSHExec, "ssh_exec"
+	if upload {
+		stepType, mapType = stepTypeSSHUpload, "ssh_upload"
+	}
+	host := stepString(dt, "host")
+	if host == "" {
+		return nil, fmt.Errorf("'host' is required for %s steps", mapType)
+	}
+	authMethod := stepString(dt, "auth_method")
+	switch {
+	case authMethod == "":
+		return nil, fmt.Errorf("'auth_method' is required for %s steps", mapType)
+	case authMethod == "uploaded_key" && stepString(dt, "ssh_key") == "":
+		return nil, fmt.Errorf("'ssh_key' is required with auth_method 'uploaded_key'")
+	case authMethod == "password" && stepString(dt, "password") == "":
+		return nil, fmt.Errorf("'password' is required with auth_method 'password'")
+	case authMethod == "password" && stepString(dt, "ssh_key") != "":
+		return nil, fmt.Errorf("'ssh_key' cannot be set with auth_method 'password'")
+	}
+
+	s := newRunnerStep(stepType, dt)
+	target := host
+	if v := stepString(dt, "target_dir"); v != "" && upload {
+		target = host + ":" + v
+	}
+	setStepProperty(s, sshTargetProperty, target)
+	if v, ok := dt["port"]; ok && v.(int) > 0 {
+		setStepProperty(s, sshPortProperty, strconv.Itoa(v.(int)))
+	}
+	setStepProperty(s, sshUsernameProperty, stepString(dt, "username"))
+	setStepProperty(s, sshAuthMethodProperty, sshAuthMethods[authMethod])
+	setStepProperty(s, sshKeyProperty, stepString(dt, "ssh_key"))
+	setStepProperty(s, sshSecureProperties["password"], stepString(dt, "password"))
+	if v, ok := dt["timeout"]; ok && v.(int) > 0 {
+		setStepProperty(s, sshTimeoutProperty, strconv.Itoa(v.(int)))
+	}
+
+	if !upload {
+		commands := stepStringList(dt, "commands")
+		if len(commands) == 0 {
+			return nil, fmt.Errorf("'commands' is required for ssh_exec steps")
+		}
+		setStepProperty(s, sshCommandsProperty, strings.Join(commands, "\n"))
+		return s, nil
+	}
+
+	rules := stepStringList(dt, "upload_rules")
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("'upload_rules' is required for ssh_upload steps")
+	}
+	setStepProperty(s, sshSourcePathProperty, strings.Join(rules, "\n"))
+	transport := stepString(dt, "transport")
+	if transport == "" {
+		transport = "scp"
+	}
+	setStepProperty(s, sshTransportProperty, sshTransportProperty+"."+transport)
+	return s, nil
+}
+
+// splitSSHUploadTarget splits the <host>[:<dir>] target of an ssh_upload step. The host can contain colons,
+// when it's an IPv6 address, bracketed or not, or is followed by a port, so the directory follows the last colon
+// after a bracketed host, and the whole target is the host when it's an IP address.
+func splitSSHUploadTarget(target string) (host string, dir string) {
+	start := 0
+	if strings.HasPrefix(target, "[") {
+		if end := strings.Index(target, "]"); end >= 0 {
+			start = end + 1
+		}
+	}
+	if start == 0 && net.ParseIP(target) != nil {
+		return target, ""
+	}
+	i := strings.LastIndex(target[start:], ":")
+	if i < 0 {
+		return target, ""
+	}
+	return target[:start+i], target[start+i+1:]
+}
+
+func flattenBuildStepSSH(s *buildStep) map[string]interface{} {
+	m := flattenRunnerStep(s, stepTypeMap[s.Type])
+	target := s.property(sshTargetProperty)
+	if s.Type == stepTypeSSHUpload {
+		target, m["target_dir"] = splitSSHUploadTarget(target)
+		m["upload_rules"] = splitStepLines(s.property(sshSourcePathProperty))
+		m["transport"] = strings.TrimPrefix(s.property(sshTransportProperty), sshTransportProperty+".")
+	} else {
+		m["commands"] = splitStepLines(s.property(sshCommandsProperty))
+	}
+	m["host"] = target
+	m["port"], _ = strconv.Atoi(s.property(sshPortProperty))
+	m["timeout"], _ = strconv.Atoi(s.property(sshTimeoutProperty))
+	m["username"] = s.property(sshUsernameProperty)
+	m["ssh_key"] = s.property(sshKeyProperty)
+	for field, method := range sshAuthMethods {
+		if s.property(sshAuthMethodProperty) == method {
+			m["auth_method"] = field
+		}
+	}
+	return m
+}
+
+// stepSharedProperties maps the properties set by the arguments shared by all step types to these arguments
+var stepSharedProperties = map[string]string{
+	"teamcity.step.mode":        "execute_step",
+	"teamcity.step.conditions":  "execute_conditions",
+	"teamcity.build.workingDir": "work_dir",
+}
+
+// expandStepGeneric builds a step of any runner type, including plugin runners and meta-runners, with the properties passed verbatim
+func expandStepGeneric(dt map[string]interface{}) (*buildStep, error) {
+	runnerType := stepString(dt, "runner_type")
+	if runnerType == "" {
+		return nil, fmt.Errorf("'runner_type' is required for generic steps")
+	}
+
+	s := newRunnerStep(runnerType, dt)
+	if v, ok := dt["properties"]; ok && v != nil {
+		for name, value := range v.(map[string]interface{}) {
+			if arg, ok := stepSharedProperties[name]; ok {
+				return nil, fmt.Errorf("property '%s' of generic steps is managed by the '%s' argument", name, arg)
+			}
+			s.Properties.AddOrReplaceValue(name, value.(string))
+		}
+	}
+	return s, nil
+}
+
+func flattenBuildStepGeneric(s *buildStep) map[string]interface{} {
+	m := flattenRunnerStep(s, "generic")
+	m["runner_type"] = s.Type
+
+	props := make(map[string]interface{})
+	if s.Properties != nil {
+		for name, value := range s.Properties.Map() {
+			if _, ok := stepSharedProperties[name]; !ok {
+				props[name] = value
+			}
+		}
+	}
+	m["properties"] = props
+	return m
+}
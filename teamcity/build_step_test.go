@@ -0,0 +1,844 @@
+package teamcity_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	api "github.com/leidruid/go-teamcity/teamcity"
+	"github.com/leidruid/terraform-provider-teamcity/teamcity"
+)
+
+func TestAccBuildConfig_StepsMaven(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBuildConfigSteps(`
+	step {
+		type = "maven"
+		name = "package"
+		goals = "clean package"
+		file = "service/pom.xml"
+		args = "-DskipTests"
+		maven_version = "3.6.3"
+		jvm_args = "-Xmx1g"
+		jdk_home = "%env.JDK_11%"
+		work_dir = "service"
+		execute_step = "execute_if_success"
+	}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					testAccCheckBuildStepProperties(&bc.ID, "package", "Maven2", map[string]string{
+						"goals":                     "clean package",
+						"pomLocation":               "service/pom.xml",
+						"runnerArgs":                "-DskipTests",
+						"maven.path":                "%teamcity.tool.maven.3.6.3%",
+						"jvmArgs":                   "-Xmx1g",
+						"target.jdk.home":           "%env.JDK_11%",
+						"teamcity.build.workingDir": "service",
+						"teamcity.step.mode":        "execute_if_success",
+					}),
+					resource.TestCheckResourceAttr(resName, "step.0.maven_version", "3.6.3"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_StepsDotnet(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBuildConfigSteps(`
+	step {
+		type = "dotnet"
+		name = "build"
+		command = "build"
+		projects = ["src/App.sln"]
+		configuration = "Release"
+		framework = "netcoreapp3.1"
+		runtime = "linux-x64"
+		version_suffix = "beta"
+	}
+
+	step {
+		type = "dotnet"
+		name = "test"
+		command = "test"
+		projects = ["tests/Unit/Unit.csproj", "tests/Integration/*.csproj"]
+		skip_build = true
+		test_filter = "Category!=Slow"
+	}
+
+	step {
+		type = "dotnet"
+		name = "publish"
+		command = "publish"
+		projects = ["src/App/App.csproj"]
+		output_dir = "out"
+	}
+
+	step {
+		type = "dotnet"
+		name = "pack"
+		command = "pack"
+		args = "--include-symbols"
+	}
+
+	step {
+		type = "dotnet"
+		name = "push"
+		command = "nuget-push"
+		projects = ["out/*.nupkg"]
+		nuget_source = "https://api.nuget.org/v3/index.json"
+		nuget_api_key = "nuget-key"
+	}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					testAccCheckBuildStepProperties(&bc.ID, "build", "dotnet", map[string]string{
+						"command":       "build",
+						"paths":         "src/App.sln",
+						"configuration": "Release",
+						"framework":     "netcoreapp3.1",
+						"runtime":       "linux-x64",
+						"versionSuffix": "beta",
+					}),
+					testAccCheckBuildStepProperties(&bc.ID, "test", "dotnet", map[string]string{
+						"command":             "test",
+						"paths":               "tests/Unit/Unit.csproj\ntests/Integration/*.csproj",
+						"skipBuild":           "true",
+						"test.testCaseFilter": "Category!=Slow",
+					}),
+					testAccCheckBuildStepProperties(&bc.ID, "push", "dotnet", map[string]string{
+						"command":             "nuget-push",
+						"paths":               "out/*.nupkg",
+						"nuget.packageSource": "https://api.nuget.org/v3/index.json",
+					}),
+					resource.TestCheckResourceAttr(resName, "step.1.projects.#", "2"),
+					resource.TestCheckResourceAttr(resName, "step.4.nuget_api_key", "nuget-key"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_StepsDotnetInvalidArgument(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBuildConfigSteps(`
+	step {
+		type = "dotnet"
+		name = "build"
+		command = "build"
+		test_filter = "Category!=Slow"
+	}
+`),
+				ExpectError: regexp.MustCompile("'test_filter' cannot be set with dotnet command 'build'"),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_StepsPython(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBuildConfigSteps(`
+	step {
+		type = "python"
+		name = "script"
+		file = "tools/release.py"
+		args = "--dry-run"
+		python_executable = "/usr/bin/python3"
+	}
+
+	step {
+		type = "python"
+		name = "module"
+		module = "pytest"
+		args = "-q tests"
+	}
+
+	step {
+		type = "python"
+		name = "code"
+		code = "print('hello')"
+	}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					testAccCheckBuildStepProperties(&bc.ID, "script", "python-runner", map[string]string{
+						"python-kind":             "1",
+						"python-script-file-name": "tools/release.py",
+						"python-script-args":      "--dry-run",
+						"python-exe":              "/usr/bin/python3",
+					}),
+					testAccCheckBuildStepProperties(&bc.ID, "module", "python-runner", map[string]string{
+						"python-kind":        "2",
+						"python-module-name": "pytest",
+						"python-script-args": "-q tests",
+					}),
+					testAccCheckBuildStepProperties(&bc.ID, "code", "python-runner", map[string]string{
+						"python-kind":        "3",
+						"python-script-code": "print('hello')",
+					}),
+					resource.TestCheckResourceAttr(resName, "step.1.module", "pytest"),
+				),
+			},
+			{
+				Config: testAccBuildConfigSteps(`
+	step {
+		type = "python"
+		name = "script"
+		file = "tools/release.py"
+		module = "release"
+	}
+`),
+				ExpectError: regexp.MustCompile("only one of 'file', 'module' or 'code' can be set for python steps"),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_StepsNodeJS(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBuildConfigSteps(`
+	step {
+		type = "nodejs"
+		name = "frontend"
+		commands = ["npm ci", "npm run build"]
+		work_dir = "frontend"
+	}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					testAccCheckBuildStepProperties(&bc.ID, "frontend", "nodejs-runner", map[string]string{
+						"shellScript":               "npm ci\nnpm run build",
+						"teamcity.build.workingDir": "frontend",
+					}),
+					resource.TestCheckResourceAttr(resName, "step.0.commands.#", "2"),
+				),
+			},
+			{
+				Config: testAccBuildConfigSteps(`
+	step {
+		type = "nodejs"
+		name = "frontend"
+		commands = ["npm ci", "npm test"]
+		work_dir = "frontend"
+	}
+`),
+				Check: testAccCheckBuildStepProperties(&bc.ID, "frontend", "nodejs-runner", map[string]string{
+					"shellScript":               "npm ci\nnpm test",
+					"teamcity.build.workingDir": "frontend",
+				}),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_StepsKotlinScript(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBuildConfigSteps(`
+	step {
+		type = "kotlin_script"
+		name = "glue"
+		code = "println(args.joinToString())"
+		args = "a b"
+	}
+
+	step {
+		type = "kotlin_script"
+		name = "release"
+		file = "tools/release.main.kts"
+		kotlin_version = "1.4.10"
+		jdk_home = "%env.JDK_11%"
+		jvm_args = "-Xmx512m"
+	}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					testAccCheckBuildStepProperties(&bc.ID, "glue", "kotlinScript", map[string]string{
+						"scriptType":    "customScript",
+						"scriptContent": "println(args.joinToString())",
+						"kotlinArgs":    "a b",
+						"kotlinPath":    "%teamcity.tool.kotlin.compiler.DEFAULT%",
+					}),
+					testAccCheckBuildStepProperties(&bc.ID, "release", "kotlinScript", map[string]string{
+						"scriptType":      "file",
+						"scriptFile":      "tools/release.main.kts",
+						"kotlinPath":      "%teamcity.tool.kotlin.compiler.1.4.10%",
+						"target.jdk.home": "%env.JDK_11%",
+						"jvmArgs":         "-Xmx512m",
+					}),
+					resource.TestCheckResourceAttr(resName, "step.0.kotlin_version", ""),
+					resource.TestCheckResourceAttr(resName, "step.1.kotlin_version", "1.4.10"),
+				),
+			},
+			{
+				Config: testAccBuildConfigSteps(`
+	step {
+		type = "kotlin_script"
+		name = "glue"
+	}
+`),
+				ExpectError: regexp.MustCompile("one of 'file' or 'code' is required for kotlin_script steps"),
+			},
+		},
+	})
+}
+
+// TestAccBuildConfig_StepsNotInState checks the step block leaves alone the steps it did not create, until imported
+func TestAccBuildConfig_StepsNotInState(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	ids := map[string]string{}
+	steps := `
+	step {
+		type = "cmd_line"
+		name = "build"
+		code = "make build"
+	}
+`
+	lint := `
+	step {
+		type = "cmd_line"
+		name = "lint"
+		code = "make lint"
+	}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBuildConfigSteps(steps),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					testAccAddBuildStep(&bc.ID, "manual"),
+				),
+			},
+			{
+				Config: testAccBuildConfigSteps(steps + lint),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildStepOrder(&bc.ID, ids, false, "build", "lint", "manual"),
+					resource.TestCheckResourceAttr(resName, "step.#", "2"),
+				),
+			},
+			{
+				ResourceName: resName,
+				ImportState:  true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 || states[0].Attributes["step.#"] != "3" || states[0].Attributes["step.2.name"] != "manual" {
+						return fmt.Errorf("expected the imported build configuration to hold all 3 steps, got %v", states)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccAddBuildStep(buildTypeID *string, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		return testAccProvider.Meta().(*teamcity.Client).AddBuildStep(*buildTypeID, name)
+	}
+}
+
+func TestAccBuildConfig_StepsSSH(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBuildConfigSteps(`
+	step {
+		type = "ssh_upload"
+		name = "upload"
+		host = "app.example.com"
+		target_dir = "/opt/app"
+		username = "deploy"
+		auth_method = "password"
+		password = "s3cr3t"
+		upload_rules = ["build/libs/*.jar", "config/** => conf"]
+		transport = "sftp"
+	}
+
+	step {
+		type = "ssh_exec"
+		name = "restart"
+		host = "app.example.com"
+		port = 2222
+		username = "deploy"
+		auth_method = "uploaded_key"
+		ssh_key = "deploy_key"
+		commands = ["systemctl restart app", "systemctl status app"]
+		timeout = 60
+	}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					testAccCheckBuildStepProperties(&bc.ID, "upload", "ssh-deploy-runner", map[string]string{
+						"jetbrains.buildServer.deployer.targetUrl":     "app.example.com:/opt/app",
+						"jetbrains.buildServer.deployer.username":      "deploy",
+						"jetbrains.buildServer.sshexec.authMethod":     "PWD",
+						"jetbrains.buildServer.deployer.sourcePath":    "build/libs/*.jar\nconfig/** => conf",
+						"jetbrains.buildServer.deployer.ssh.transport": "jetbrains.buildServer.deployer.ssh.transport.sftp",
+					}),
+					testAccCheckBuildStepProperties(&bc.ID, "restart", "ssh-exec-runner", map[string]string{
+						"jetbrains.buildServer.deployer.targetUrl": "app.example.com",
+						"jetbrains.buildServer.sshexec.port":       "2222",
+						"jetbrains.buildServer.sshexec.authMethod": "UPLOADED_KEY",
+						"teamcitySshKey":                        "deploy_key",
+						"jetbrains.buildServer.sshexec.command": "systemctl restart app\nsystemctl status app",
+						"jetbrains.buildServer.sshexec.timeout": "60",
+					}),
+					resource.TestCheckResourceAttr(resName, "step.0.password", "s3cr3t"),
+					resource.TestCheckResourceAttr(resName, "step.0.host", "app.example.com"),
+					resource.TestCheckResourceAttr(resName, "step.0.target_dir", "/opt/app"),
+					resource.TestCheckResourceAttr(resName, "step.1.commands.#", "2"),
+				),
+			},
+			{
+				Config: testAccBuildConfigSteps(`
+	step {
+		type = "ssh_upload"
+		name = "upload"
+		host = "app.example.com"
+		username = "deploy"
+		auth_method = "password"
+		password = "s3cr3t"
+		upload_rules = ["build/libs/*.jar"]
+	}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildStepProperties(&bc.ID, "upload", "ssh-deploy-runner", map[string]string{
+						"jetbrains.buildServer.deployer.targetUrl":     "app.example.com",
+						"jetbrains.buildServer.deployer.ssh.transport": "jetbrains.buildServer.deployer.ssh.transport.scp",
+					}),
+					resource.TestCheckResourceAttr(resName, "step.0.transport", "scp"),
+				),
+			},
+			{
+				Config: testAccBuildConfigSteps(`
+	step {
+		type = "ssh_exec"
+		name = "restart"
+		host = "app.example.com"
+		username = "deploy"
+		auth_method = "uploaded_key"
+		commands = ["systemctl restart app"]
+	}
+`),
+				ExpectError: regexp.MustCompile("'ssh_key' is required with auth_method 'uploaded_key'"),
+			},
+		},
+	})
+}
+
+// TestAccBuildConfig_StepsSSHUploadTargets checks hosts containing colons are read back from the upload target without a diff
+func TestAccBuildConfig_StepsSSHUploadTargets(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBuildConfigSteps(`
+	step {
+		type = "ssh_upload"
+		name = "bracketed"
+		host = "[2001:db8::1]"
+		target_dir = "/opt/app"
+		username = "deploy"
+		auth_method = "password"
+		password = "s3cr3t"
+		upload_rules = ["build/libs/*.jar"]
+	}
+
+	step {
+		type = "ssh_upload"
+		name = "ipv6"
+		host = "2001:db8::2"
+		username = "deploy"
+		auth_method = "password"
+		password = "s3cr3t"
+		upload_rules = ["build/libs/*.jar"]
+	}
+
+	step {
+		type = "ssh_upload"
+		name = "with_port"
+		host = "app.example.com:2222"
+		target_dir = "releases"
+		username = "deploy"
+		auth_method = "password"
+		password = "s3cr3t"
+		upload_rules = ["build/libs/*.jar"]
+	}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					testAccCheckBuildStepProperties(&bc.ID, "bracketed", "ssh-deploy-runner", map[string]string{
+						"jetbrains.buildServer.deployer.targetUrl": "[2001:db8::1]:/opt/app",
+					}),
+					testAccCheckBuildStepProperties(&bc.ID, "ipv6", "ssh-deploy-runner", map[string]string{
+						"jetbrains.buildServer.deployer.targetUrl": "2001:db8::2",
+					}),
+					testAccCheckBuildStepProperties(&bc.ID, "with_port", "ssh-deploy-runner", map[string]string{
+						"jetbrains.buildServer.deployer.targetUrl": "app.example.com:2222:releases",
+					}),
+					resource.TestCheckResourceAttr(resName, "step.0.host", "[2001:db8::1]"),
+					resource.TestCheckResourceAttr(resName, "step.0.target_dir", "/opt/app"),
+					resource.TestCheckResourceAttr(resName, "step.1.host", "2001:db8::2"),
+					resource.TestCheckResourceAttr(resName, "step.1.target_dir", ""),
+					resource.TestCheckResourceAttr(resName, "step.2.host", "app.example.com:2222"),
+					resource.TestCheckResourceAttr(resName, "step.2.target_dir", "releases"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_StepsGeneric(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBuildConfigSteps(`
+	step {
+		type = "generic"
+		name = "helm"
+		runner_type = "jetbrains.helm"
+		execute_step = "execute_always"
+		properties = {
+			"teamcity.helm.command" = "helm-upgrade"
+			"chart" = "charts/app"
+			"secure:helm.password" = "registry-password"
+		}
+	}
+
+	step {
+		type = "generic"
+		name = "shell"
+		runner_type = "simpleRunner"
+		work_dir = "scripts"
+		properties = {
+			"script.content" = "./release.sh"
+			"use.custom.script" = "true"
+		}
+	}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					testAccCheckBuildStepProperties(&bc.ID, "helm", "jetbrains.helm", map[string]string{
+						"teamcity.helm.command": "helm-upgrade",
+						"chart":                 "charts/app",
+						"teamcity.step.mode":    "execute_always",
+					}),
+					testAccCheckBuildStepProperties(&bc.ID, "shell", "simpleRunner", map[string]string{
+						"script.content":            "./release.sh",
+						"use.custom.script":         "true",
+						"teamcity.build.workingDir": "scripts",
+					}),
+					resource.TestCheckResourceAttr(resName, "step.0.properties.%", "3"),
+					resource.TestCheckResourceAttr(resName, "step.0.properties.secure:helm.password", "registry-password"),
+					resource.TestCheckResourceAttr(resName, "step.1.type", "generic"),
+					resource.TestCheckResourceAttr(resName, "step.1.properties.%", "2"),
+				),
+			},
+			{
+				Config: testAccBuildConfigSteps(`
+	step {
+		type = "generic"
+		name = "helm"
+		runner_type = "jetbrains.helm"
+		properties = {
+			"teamcity.helm.command" = "helm-upgrade"
+			"chart" = "charts/app"
+			"teamcity.step.mode" = "execute_always"
+		}
+	}
+`),
+				ExpectError: regexp.MustCompile("property 'teamcity.step.mode' of generic steps is managed by the 'execute_step' argument"),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_StepsUpdateInPlace(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	ids := map[string]string{}
+
+	first := `
+	step {
+		type = "cmd_line"
+		name = "first"
+		code = "echo first"
+	}
+`
+	second := func(goals string, enabled bool) string {
+		return fmt.Sprintf(`
+	step {
+		type = "maven"
+		name = "second"
+		goals = "%s"
+		enabled = %t
+	}
+`, goals, enabled)
+	}
+	third := `
+	step {
+		type = "generic"
+		name = "third"
+		runner_type = "jetbrains.helm"
+		properties = {
+			chart = "charts/app"
+		}
+	}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBuildConfigSteps(first + second("package", true) + third),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					testAccCheckBuildStepOrder(&bc.ID, ids, false, "first", "second", "third"),
+					resource.TestCheckResourceAttr(resName, "step.1.enabled", "true"),
+				),
+			},
+			{
+				Config: testAccBuildConfigSteps(first + second("verify", false) + third),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildStepOrder(&bc.ID, ids, true, "first", "second", "third"),
+					testAccCheckBuildStepDisabled(&bc.ID, "second", true),
+					testAccCheckBuildStepProperties(&bc.ID, "second", "Maven2", map[string]string{"goals": "verify"}),
+					resource.TestCheckResourceAttr(resName, "step.1.enabled", "false"),
+				),
+			},
+			{
+				Config: testAccBuildConfigSteps(third + first + second("verify", false)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildStepOrder(&bc.ID, ids, true, "third", "first", "second"),
+					testAccCheckBuildStepDisabled(&bc.ID, "second", true),
+					resource.TestCheckResourceAttr(resName, "step.0.runner_type", "jetbrains.helm"),
+				),
+			},
+			{
+				Config: testAccBuildConfigSteps(`
+	step {
+		type = "nodejs"
+		name = "added"
+		commands = ["npm ci"]
+	}
+` + third + second("verify", true)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildStepOrder(&bc.ID, ids, true, "added", "third", "second"),
+					testAccCheckBuildStepDisabled(&bc.ID, "second", false),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_StepsInheritedFromTemplate(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	ids := map[string]string{}
+
+	config := func(steps string) string {
+		return fmt.Sprintf(`
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_template" {
+	name = "build template"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+	is_template = true
+
+	step {
+		type = "cmd_line"
+		name = "checkout"
+		code = "git lfs pull"
+	}
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+	templates = [ "${teamcity_build_config.build_configuration_template.id}" ]
+%s
+}
+`, steps)
+	}
+	build := func(code string) string {
+		return fmt.Sprintf(`
+	step {
+		type = "cmd_line"
+		name = "build"
+		code = "%s"
+	}
+`, code)
+	}
+	test := `
+	step {
+		type = "cmd_line"
+		name = "test"
+		code = "make test"
+	}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config(build("make build") + test),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					testAccCheckBuildStepOrder(&bc.ID, ids, false, "checkout", "build", "test"),
+					resource.TestCheckResourceAttr(resName, "step.#", "2"),
+				),
+			},
+			{
+				// Inherited steps are neither updated nor sent back when reordering, which would detach them from the template
+				Config: config(test + build("make all")),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildStepOrder(&bc.ID, ids, true, "checkout", "test", "build"),
+					resource.TestCheckResourceAttr(resName, "step.#", "2"),
+					resource.TestCheckResourceAttr(resName, "step.1.code", "make all"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckBuildStepOrder checks the steps of a build configuration are in the given order.
+// When keepIDs is true, the steps recorded in ids by a previous check must have kept their ID.
+func testAccCheckBuildStepOrder(buildTypeID *string, ids map[string]string, keepIDs bool, names ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		steps, err := testAccProvider.Meta().(*teamcity.Client).BuildStepSummaries(*buildTypeID)
+		if err != nil {
+			return err
+		}
+
+		var actual []string
+		for _, step := range steps {
+			actual = append(actual, step.Name)
+			if id, ok := ids[step.Name]; ok && keepIDs && id != step.ID {
+				return fmt.Errorf("step '%s' was recreated: ID changed from '%s' to '%s'", step.Name, id, step.ID)
+			}
+			ids[step.Name] = step.ID
+		}
+		if fmt.Sprint(actual) != fmt.Sprint(names) {
+			return fmt.Errorf("steps order: got %v, expected %v", actual, names)
+		}
+		return nil
+	}
+}
+
+func testAccCheckBuildStepDisabled(buildTypeID *string, name string, disabled bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		steps, err := testAccProvider.Meta().(*teamcity.Client).BuildStepSummaries(*buildTypeID)
+		if err != nil {
+			return err
+		}
+		for _, step := range steps {
+			if step.Name == name {
+				if step.Disabled != disabled {
+					return fmt.Errorf("step '%s' disabled: got %t, expected %t", name, step.Disabled, disabled)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("step '%s' not found", name)
+	}
+}
+
+// testAccCheckBuildStepProperties checks the runner type and the properties of a build step, ignoring properties not in expected
+func testAccCheckBuildStepProperties(buildTypeID *string, name string, stepType string, expected map[string]string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*teamcity.Client)
+		actualType, props, err := client.BuildStepProperties(*buildTypeID, name)
+		if err != nil {
+			return err
+		}
+		if actualType != stepType {
+			return fmt.Errorf("step '%s' type: got '%s', expected '%s'", name, actualType, stepType)
+		}
+		for k, v := range expected {
+			if props[k] != v {
+				return fmt.Errorf("step '%s' property '%s': got '%s', expected '%s'", name, k, props[k], v)
+			}
+		}
+		return nil
+	}
+}
+
+func testAccBuildConfigSteps(steps string) string {
+	return fmt.Sprintf(`
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+%s
+}
+`, steps)
+}
@@ -0,0 +1,162 @@
+package teamcity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+
+	api "github.com/leidruid/go-teamcity/teamcity"
+)
+
+// Client is the provider meta handed to every resource and data source.
+// It embeds the go-teamcity client and keeps what is needed to issue REST calls the library does not cover,
+// or that the library would send through http.DefaultClient instead of the configured transport.
+type Client struct {
+	*api.Client
+
+	address    string
+	auth       api.Auth
+	restBase   string
+	username   string
+	password   string
+	token      string
+	httpClient *http.Client
+
+	// server is detected when the provider is configured
+	server *serverInfo
+
+	// stopContext is cancelled when Terraform asks the provider to stop, e.g. on Ctrl-C
+	stopContext context.Context
+}
+
+func newClient(c *Config, auth api.Auth, apiClient *api.Client, httpClient *http.Client) *Client {
+	out := &Client{
+		Client:      apiClient,
+		address:     c.Address,
+		auth:        auth,
+		username:    c.Username,
+		password:    c.Password,
+		token:       c.AccessToken,
+		httpClient:  httpClient,
+		stopContext: context.Background(),
+	}
+	if c.AccessToken != "" {
+		out.restBase = c.Address + "/app/rest/"
+	} else {
+		out.restBase = c.Address + "/httpAuth/app/rest/"
+	}
+	return out
+}
+
+// withContext returns a copy of this client sending every request with the given context, so requests are aborted when it is done
+func (c *Client) withContext(ctx context.Context) (*Client, error) {
+	httpClient := &http.Client{
+		Transport: &contextTransport{base: c.httpClient.Transport, ctx: ctx},
+	}
+	apiClient, err := api.NewClientWithAddress(c.auth, c.address, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	out := *c
+	out.Client = apiClient
+	out.httpClient = httpClient
+	return &out, nil
+}
+
+// formFile is a request body holding a single file, sent as multipart/form-data
+type formFile struct {
+	field    string
+	fileName string
+	content  string
+}
+
+// newRequest builds an authenticated request for the given path, relative to the REST API root.
+// A non-nil body is sent as JSON, unless it is a string, which is sent as text/plain, or a formFile.
+func (c *Client) newRequest(method, path string, body interface{}) (*http.Request, error) {
+	var reader io.Reader
+	var contentType string
+
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reader = bytes.NewBufferString(b)
+		contentType = "text/plain; charset=utf-8"
+	case formFile:
+		buf := &bytes.Buffer{}
+		w := multipart.NewWriter(buf)
+		part, err := w.CreateFormFile(b.field, b.fileName)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(part, b.content); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		reader = buf
+		contentType = w.FormDataContentType()
+	default:
+		dt, err := json.Marshal(b)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewBuffer(dt)
+		contentType = "application/json"
+	}
+
+	req, err := http.NewRequest(method, c.restBase+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	} else {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	return req, nil
+}
+
+// doRequest sends a request to the REST API and decodes a JSON response into out, when out is not nil.
+// resourceDescription is used to build error messages, like go-teamcity does.
+func (c *Client) doRequest(method, path string, body interface{}, out interface{}, resourceDescription string) error {
+	req, err := c.newRequest(method, path, body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	dt, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &apiError{
+			StatusCode:          resp.StatusCode,
+			Method:              method,
+			ResourceDescription: resourceDescription,
+			Body:                string(dt),
+		}
+	}
+
+	if out == nil || len(dt) == 0 {
+		return nil
+	}
+	return json.Unmarshal(dt, out)
+}
@@ -1,19 +1,217 @@
 package teamcity
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	api "github.com/leidruid/go-teamcity/teamcity"
+	"golang.org/x/net/http/httpproxy"
 )
 
 // Config Used to configure an api client for TeamCity
 type Config struct {
-	Address  string
-	Username string
-	Password string
+	Address     string
+	Username    string
+	Password    string
+	AccessToken string
+
+	// MaxRetries is the number of times a failed request is retried. Zero disables retries.
+	MaxRetries   int
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+
+	// MaxConcurrentRequests limits the number of requests in flight. Zero means no limit.
+	MaxConcurrentRequests int
+
+	// CACertFile and CACertPEM add a CA bundle, from a file or inline, to the system trust store
+	CACertFile string
+	CACertPEM  string
+	// ClientCert and ClientKey hold a PEM encoded certificate and key, or paths to files containing them, for mutual TLS
+	ClientCert         string
+	ClientKey          string
+	InsecureSkipVerify bool
+
+	// ProxyURL overrides the proxy from the HTTP_PROXY/HTTPS_PROXY environment variables. NoProxy follows the NO_PROXY format.
+	ProxyURL string
+	NoProxy  string
+	// Headers are added to every request
+	Headers map[string]string
 }
 
 // Client Returns a new TeamCity api client configured with this instance parameters
-func (c *Config) Client() (*api.Client, error) {
-	return api.NewWithAddress(c.Username, c.Password, c.Address, http.DefaultClient)
+func (c *Config) Client() (*Client, error) {
+	auth, err := c.auth()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.RetryWaitMin > c.RetryWaitMax {
+		return nil, fmt.Errorf("'retry_wait_min' (%s) must not be greater than 'retry_wait_max' (%s)", c.RetryWaitMin, c.RetryWaitMax)
+	}
+
+	transport, err := c.transport()
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{
+		Transport: transport,
+	}
+	apiClient, err := api.NewClientWithAddress(auth, c.Address, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return newClient(c, auth, apiClient, httpClient), nil
+}
+
+// auth validates that exactly one authentication mode is configured and returns it
+func (c *Config) auth() (api.Auth, error) {
+	hasBasic := c.Username != "" || c.Password != ""
+
+	if c.AccessToken != "" {
+		if hasBasic {
+			return nil, fmt.Errorf("'access_token' and 'username'/'password' are mutually exclusive, configure only one authentication mode")
+		}
+		return api.TokenAuth(c.AccessToken), nil
+	}
+
+	if !hasBasic {
+		return nil, fmt.Errorf("no authentication configured, either 'access_token' or 'username' and 'password' are required")
+	}
+	if c.Username == "" || c.Password == "" {
+		return nil, fmt.Errorf("both 'username' and 'password' are required when using basic authentication")
+	}
+	return api.BasicAuth(c.Username, c.Password), nil
+}
+
+// transport builds the chain of round-trippers used by every request sent to TeamCity
+func (c *Config) transport() (http.RoundTripper, error) {
+	base := newBaseTransport()
+
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		base.TLSClientConfig = tlsConfig
+	}
+
+	if c.ProxyURL != "" {
+		proxy, err := c.proxyFunc()
+		if err != nil {
+			return nil, err
+		}
+		base.Proxy = proxy
+	}
+
+	var rt http.RoundTripper = base
+	if len(c.Headers) > 0 {
+		rt = &headerTransport{base: rt, headers: c.Headers}
+	}
+	rt = &loggingTransport{base: rt}
+	if c.MaxConcurrentRequests > 0 {
+		// Below the retry transport, so that requests waiting to be retried do not hold a slot
+		rt = newSemaphoreTransport(rt, c.MaxConcurrentRequests)
+	}
+	if c.MaxRetries > 0 {
+		rt = newRetryTransport(rt, c.MaxRetries, c.RetryWaitMin, c.RetryWaitMax)
+	}
+	return rt, nil
+}
+
+// proxyFunc sends every request through ProxyURL, except for hosts matching NoProxy
+func (c *Config) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	u, err := url.Parse(c.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'proxy_url': %s", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid 'proxy_url' '%s': scheme and host are required, e.g. http://proxy.local:3128", u.Redacted())
+	}
+
+	proxy := (&httpproxy.Config{
+		HTTPProxy:  c.ProxyURL,
+		HTTPSProxy: c.ProxyURL,
+		NoProxy:    c.NoProxy,
+	}).ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}, nil
+}
+
+// tlsConfig returns a dedicated TLS configuration, or nil when none of the TLS settings are used
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	if c.CACertFile == "" && c.CACertPEM == "" && c.ClientCert == "" && c.ClientKey == "" && !c.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	if c.CACertFile != "" && c.CACertPEM != "" {
+		return nil, fmt.Errorf("'ca_cert_file' and 'ca_cert_pem' are mutually exclusive")
+	}
+
+	out := &tls.Config{
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CACertFile != "" || c.CACertPEM != "" {
+		caPEM := []byte(c.CACertPEM)
+		source := "ca_cert_pem"
+		if c.CACertFile != "" {
+			dt, err := ioutil.ReadFile(c.CACertFile)
+			if err != nil {
+				return nil, fmt.Errorf("error reading 'ca_cert_file': %s", err)
+			}
+			caPEM = dt
+			source = fmt.Sprintf("ca_cert_file '%s'", c.CACertFile)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("%s does not contain any valid PEM encoded certificate", source)
+		}
+		out.RootCAs = pool
+	}
+
+	if c.ClientCert != "" || c.ClientKey != "" {
+		if c.ClientCert == "" || c.ClientKey == "" {
+			return nil, fmt.Errorf("both 'client_cert' and 'client_key' are required for client certificate authentication")
+		}
+		certPEM, err := readPEMOrFile(c.ClientCert, "client_cert")
+		if err != nil {
+			return nil, err
+		}
+		keyPEM, err := readPEMOrFile(c.ClientKey, "client_key")
+		if err != nil {
+			return nil, err
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid 'client_cert'/'client_key' pair: %s", err)
+		}
+		out.Certificates = []tls.Certificate{cert}
+	}
+
+	return out, nil
+}
+
+// readPEMOrFile returns v itself when it holds PEM content, otherwise the content of the file it points to
+func readPEMOrFile(v string, argument string) ([]byte, error) {
+	if strings.Contains(v, "-----BEGIN") {
+		return []byte(v), nil
+	}
+	dt, err := ioutil.ReadFile(v)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is neither PEM content nor a readable file: %s", argument, err)
+	}
+	return dt, nil
 }
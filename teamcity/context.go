@@ -0,0 +1,64 @@
+package teamcity
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// The plugin SDK in use predates context-aware CRUD functions, so resources implement the signatures below and are adapted with createContext, readContext, updateContext and deleteContext.
+// The context given to CRUD functions is bound to the operation timeout and cancelled when the provider is stopped,
+// and the client passed as meta sends every request with it.
+// defaultTimeout applies to create, update and delete operations unless overridden with a timeouts block
+const defaultTimeout = 5 * time.Minute
+
+type contextFunc func(ctx context.Context, d *schema.ResourceData, meta interface{}) error
+
+type stateContextFunc func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error)
+
+func createContext(f contextFunc) schema.CreateFunc {
+	return withTimeout(schema.TimeoutCreate, f)
+}
+
+func readContext(f contextFunc) schema.ReadFunc {
+	return withTimeout(schema.TimeoutRead, f)
+}
+
+func updateContext(f contextFunc) schema.UpdateFunc {
+	return withTimeout(schema.TimeoutUpdate, f)
+}
+
+func deleteContext(f contextFunc) schema.DeleteFunc {
+	return withTimeout(schema.TimeoutDelete, f)
+}
+
+func importStateContext(f stateContextFunc) schema.StateFunc {
+	return func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+		ctx, cancel := operationContext(meta.(*Client), d.Timeout(schema.TimeoutRead))
+		defer cancel()
+
+		client, err := meta.(*Client).withContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return f(ctx, d, client)
+	}
+}
+
+func withTimeout(key string, f contextFunc) func(*schema.ResourceData, interface{}) error {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		ctx, cancel := operationContext(meta.(*Client), d.Timeout(key))
+		defer cancel()
+
+		client, err := meta.(*Client).withContext(ctx)
+		if err != nil {
+			return err
+		}
+		return f(ctx, d, client)
+	}
+}
+
+func operationContext(c *Client, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(c.stopContext, timeout)
+}
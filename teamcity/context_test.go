@@ -0,0 +1,92 @@
+package teamcity_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/leidruid/terraform-provider-teamcity/teamcity"
+)
+
+func TestProvider_StopAbortsInFlightRequests(t *testing.T) {
+	testAccStopAbortsOperation(t, "teamcity_project", "Project1", func(r *schema.Resource, d *schema.ResourceData, meta interface{}) error {
+		return r.Read(d, meta)
+	})
+}
+
+func TestProvider_StopAbortsBuildConfigRequests(t *testing.T) {
+	t.Run("read", func(t *testing.T) {
+		testAccStopAbortsOperation(t, "teamcity_build_config", "Project1_Build", func(r *schema.Resource, d *schema.ResourceData, meta interface{}) error {
+			return r.Read(d, meta)
+		})
+	})
+	t.Run("update", func(t *testing.T) {
+		testAccStopAbortsOperation(t, "teamcity_build_config", "Project1_Build", func(r *schema.Resource, d *schema.ResourceData, meta interface{}) error {
+			return r.Update(d, meta)
+		})
+	})
+}
+
+func TestProvider_StopAbortsBuildConfigChildRequests(t *testing.T) {
+	for _, name := range []string{"teamcity_agent_requirement", "teamcity_snapshot_dependency", "teamcity_artifact_dependency"} {
+		t.Run(name, func(t *testing.T) {
+			testAccStopAbortsOperation(t, name, "Requirement1", func(r *schema.Resource, d *schema.ResourceData, meta interface{}) error {
+				d.Set("build_config_id", "Project1_Build")
+				return r.Read(d, meta)
+			})
+		})
+	}
+}
+
+// testAccStopAbortsOperation runs an operation of a resource against a server that never answers,
+// and checks stopping the provider aborts it
+func testAccStopAbortsOperation(t *testing.T, name string, id string, op func(*schema.Resource, *schema.ResourceData, interface{}) error) {
+	received := make(chan struct{}, 1)
+	release := make(chan struct{})
+	p := testProviderWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+	}))
+	defer close(release)
+
+	r := p.ResourcesMap[name]
+	d := r.TestResourceData()
+	d.SetId(id)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- op(r, d, p.Meta())
+	}()
+
+	select {
+	case <-received:
+	case err := <-done:
+		t.Fatalf("%s: operation returned without sending a request through the provider client: %v", name, err)
+	}
+	if err := p.Stop(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatalf("%s: expected the operation to fail once the provider is stopped", name)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%s: operation was not aborted when the provider was stopped", name)
+	}
+}
+
+func TestProvider_ResourceTimeoutDefaults(t *testing.T) {
+	p := teamcity.Provider().(*schema.Provider)
+	for name, r := range p.ResourcesMap {
+		if r.Timeouts == nil || r.Timeouts.Create == nil || r.Timeouts.Delete == nil {
+			t.Errorf("%s: expected default create and delete timeouts", name)
+			continue
+		}
+		if r.Update != nil && r.Timeouts.Update == nil {
+			t.Errorf("%s: expected a default update timeout", name)
+		}
+	}
+}
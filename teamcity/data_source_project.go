@@ -1,6 +1,7 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -9,7 +10,7 @@ import (
 
 func dataSourceProject() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceProjectRead,
+		Read: readContext(dataSourceProjectRead),
 		Schema: map[string]*schema.Schema{
 			"project_id": {
 				Type:          schema.TypeString,
@@ -36,8 +37,8 @@ func dataSourceProject() *schema.Resource {
 	}
 }
 
-func dataSourceProjectRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func dataSourceProjectRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	var id, name string
 	var dt *api.Project
 
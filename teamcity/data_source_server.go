@@ -0,0 +1,54 @@
+package teamcity
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceServer() *schema.Resource {
+	return &schema.Resource{
+		Read: readContext(dataSourceServerRead),
+		Schema: map[string]*schema.Schema{
+			"version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"build_number": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceServerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	// Detection at configure time is allowed to fail, it is retried here to report the error
+	if client.server == nil {
+		if err := client.detectServer(); err != nil {
+			return err
+		}
+	}
+
+	// The root URL is unset until configured in the TeamCity administration
+	if client.server.WebURL != "" {
+		d.SetId(client.server.WebURL)
+	} else {
+		d.SetId(client.address)
+	}
+	if err := d.Set("version", client.server.Version); err != nil {
+		return err
+	}
+	if err := d.Set("build_number", client.server.BuildNumber); err != nil {
+		return err
+	}
+	if err := d.Set("url", client.server.WebURL); err != nil {
+		return err
+	}
+	return nil
+}
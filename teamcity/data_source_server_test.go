@@ -0,0 +1,85 @@
+package teamcity_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/leidruid/terraform-provider-teamcity/teamcity"
+)
+
+func testServerProvider(t *testing.T, status int, body string) (*schema.Provider, error) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/app/rest/server" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := teamcity.Provider().(*schema.Provider)
+	err := p.Configure(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"address":      srv.URL,
+		"access_token": "token",
+		"max_retries":  0,
+	}))
+	return p, err
+}
+
+func TestDataSourceServer_Read(t *testing.T) {
+	p, err := testServerProvider(t, http.StatusOK, `{"version":"2020.1.3 (build 78938)","buildNumber":"78938","webUrl":"https://teamcity.example.com"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ds := p.DataSourcesMap["teamcity_server"]
+	d := ds.TestResourceData()
+	if err := ds.Read(d, p.Meta()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]string{
+		"version":      "2020.1.3 (build 78938)",
+		"build_number": "78938",
+		"url":          "https://teamcity.example.com",
+	}
+	for k, v := range expected {
+		if got := d.Get(k).(string); got != v {
+			t.Errorf("expected %s to be %q, got %q", k, v, got)
+		}
+	}
+}
+
+func TestDataSourceServer_IDFallsBackToAddress(t *testing.T) {
+	p, err := testServerProvider(t, http.StatusOK, `{"version":"2020.1.3 (build 78938)","buildNumber":"78938"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ds := p.DataSourcesMap["teamcity_server"]
+	d := ds.TestResourceData()
+	if err := ds.Read(d, p.Meta()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.HasPrefix(d.Id(), "http://127.0.0.1:") {
+		t.Fatalf("expected the ID to be the configured address, got %q", d.Id())
+	}
+}
+
+func TestProvider_ConfigureWhenServerVersionUnavailable(t *testing.T) {
+	p, err := testServerProvider(t, http.StatusForbidden, `Access denied`)
+	if err != nil {
+		t.Fatalf("expected configure to succeed without the server version, got %s", err)
+	}
+
+	ds := p.DataSourcesMap["teamcity_server"]
+	err = ds.Read(ds.TestResourceData(), p.Meta())
+	if err == nil || !strings.Contains(err.Error(), "error detecting TeamCity server version") {
+		t.Fatalf("expected server version detection error, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package teamcity
+
+import (
+	"fmt"
+
+	api "github.com/leidruid/go-teamcity/teamcity"
+)
+
+// Helpers exported to the acceptance tests, which live in the teamcity_test package
+
+// IsNotFound reports whether err was caused by TeamCity answering 404 Not Found
+var IsNotFound = isNotFound
+
+// VcsRootProperties retrieves the properties of a VCS Root of any type, since go-teamcity only reads Git VCS Roots
+func (c *Client) VcsRootProperties(id string) (map[string]string, error) {
+	dt, err := c.getVcsRoot(id)
+	if err != nil {
+		return nil, err
+	}
+	if dt.Properties == nil {
+		return map[string]string{}, nil
+	}
+	return dt.Properties.Map(), nil
+}
+
+// HasSshKey reports whether a key with the given name is uploaded into the project
+func (c *Client) HasSshKey(projectID string, name string) (bool, error) {
+	dt, err := c.getSshKey(projectID, name)
+	return dt != nil, err
+}
+
+// ProjectFeatureProperties retrieves the type and properties of a project feature
+func (c *Client) ProjectFeatureProperties(projectID string, id string) (string, map[string]string, error) {
+	dt, err := c.getProjectFeature(projectID, id)
+	if err != nil {
+		return "", nil, err
+	}
+	if dt.Properties == nil {
+		return dt.Type, map[string]string{}, nil
+	}
+	return dt.Type, dt.Properties.Map(), nil
+}
+
+// ProjectParameterSpec retrieves the value and raw spec of a project parameter
+func (c *Client) ProjectParameterSpec(projectID string, name string) (string, string, error) {
+	return c.parameterSpec(projectPath(projectID), name)
+}
+
+// BuildConfigParameterSpec retrieves the value and raw spec of a build configuration parameter
+func (c *Client) BuildConfigParameterSpec(buildConfigID string, name string) (string, string, error) {
+	return c.parameterSpec(buildTypePath(buildConfigID), name)
+}
+
+// SetProjectParameterValue replaces the value of a project parameter with a plain text PUT
+func (c *Client) SetProjectParameterValue(projectID string, name string, value string) error {
+	return c.putParameterValue(projectPath(projectID), name, value)
+}
+
+func (c *Client) parameterSpec(ownerPath string, name string) (string, string, error) {
+	dt, err := c.getParameter(ownerPath, name)
+	if err != nil {
+		return "", "", err
+	}
+	if dt.Type == nil {
+		return dt.Value, "", nil
+	}
+	return dt.Value, dt.Type.RawValue, nil
+}
+
+// BuildStepProperties retrieves the runner type and properties of the build step with the given name
+func (c *Client) BuildStepProperties(buildTypeID string, name string) (string, map[string]string, error) {
+	steps, err := c.getBuildSteps(buildTypeID)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, s := range steps {
+		if s.Name == name {
+			return s.Type, s.Properties.Map(), nil
+		}
+	}
+	return "", nil, fmt.Errorf("build step '%s' not found", name)
+}
+
+// BuildStepSummary is the ID, name and state of a build step
+type BuildStepSummary struct {
+	ID       string
+	Name     string
+	Disabled bool
+}
+
+// BuildStepSummaries retrieves the steps of a build configuration, in order
+func (c *Client) BuildStepSummaries(buildTypeID string) ([]BuildStepSummary, error) {
+	steps, err := c.getBuildSteps(buildTypeID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]BuildStepSummary, 0, len(steps))
+	for _, s := range steps {
+		out = append(out, BuildStepSummary{ID: s.ID, Name: s.Name, Disabled: s.Disabled})
+	}
+	return out, nil
+}
+
+// DeleteBuildStep deletes the build step with the given teamcity_build_step ID
+func (c *Client) DeleteBuildStep(id string) error {
+	buildTypeID, stepID, err := parseBuildStepID(id)
+	if err != nil {
+		return err
+	}
+	return deleteStep(c, buildTypeID, stepID)
+}
+
+// AddBuildStep adds a command line step to a build configuration, like a step added outside Terraform
+func (c *Client) AddBuildStep(buildTypeID string, name string) error {
+	s := &buildStep{Name: name, Type: "simpleRunner", Properties: api.NewPropertiesEmpty()}
+	s.Properties.AddOrReplaceValue("script.content", "echo "+name)
+	_, err := c.addBuildStep(buildTypeID, s)
+	return err
+}
@@ -0,0 +1,375 @@
+package teamcity_test
+
+import (
+	"strings"
+)
+
+// --- build configurations and templates
+
+func (f *fakeTeamCity) routeBuildTypes(r *fakeRequest) (interface{}, error) {
+	s := r.segments
+	if len(s) == 1 {
+		if r.method == "POST" {
+			return f.createBuildType(r)
+		}
+		return nil, fakeBadRequest("unsupported %s on buildTypes", r.method)
+	}
+
+	id, ok := f.locateBuildType(s[1])
+	if !ok {
+		return nil, fakeNotFound("No build type or template is found by id, internal id or name '%s'", s[1])
+	}
+	bt := f.buildTypes[id]
+
+	if len(s) == 2 {
+		switch r.method {
+		case "GET":
+			return f.renderBuildType(id), nil
+		case "DELETE":
+			delete(f.buildTypes, id)
+			return nil, nil
+		}
+		return nil, fakeBadRequest("unsupported %s on build type", r.method)
+	}
+
+	switch s[2] {
+	case "name", "description":
+		if r.method == "PUT" {
+			bt.fields[s[2]] = string(r.body)
+		}
+		return stringField(bt.fields, s[2]), nil
+	case "settings":
+		return f.routeSettings(r, bt, s[3:])
+	case "parameters":
+		return f.routeParameters(r, bt.fields, s[3:])
+	}
+
+	if _, ok := fakeCollections[s[2]]; ok {
+		return f.routeBuildTypeCollection(r, bt, s[2], s[3:])
+	}
+	return nil, fakeNotFound("unsupported build type resource '%s'", s[2])
+}
+
+func (f *fakeTeamCity) locateBuildType(locator string) (string, bool) {
+	fields := make(map[string]fakeObject, len(f.buildTypes))
+	for id, bt := range f.buildTypes {
+		fields[id] = bt.fields
+	}
+	return locate(locator, fields)
+}
+
+func (f *fakeTeamCity) createBuildType(r *fakeRequest) (interface{}, error) {
+	body, err := r.decode()
+	if err != nil {
+		return nil, err
+	}
+	name := stringField(body, "name")
+	if name == "" {
+		return nil, fakeBadRequest("Build configuration name cannot be empty")
+	}
+	projectID := stringField(body, "projectId")
+	if projectID == "" {
+		projectID = stringField(body, "project", "id")
+	}
+	if _, ok := f.projects[projectID]; !ok {
+		return nil, fakeNotFound("No project found by locator 'id:%s'", projectID)
+	}
+
+	id := stringField(body, "id")
+	if id == "" {
+		id = f.generateID(projectID, name, func(id string) bool { _, ok := f.buildTypes[id]; return ok })
+	}
+
+	templateFlag, _ := body["templateFlag"].(bool)
+	bt := &fakeBuildType{
+		fields: fakeObject{
+			"id":           id,
+			"name":         name,
+			"description":  stringField(body, "description"),
+			"projectId":    projectID,
+			"templateFlag": templateFlag,
+			"settings":     fakeSettings(body["settings"]),
+			"parameters":   fakeProperties(body["parameters"]),
+		},
+		items: map[string][]fakeObject{},
+	}
+	f.buildTypes[id] = bt
+
+	if steps, ok := body["steps"].(fakeObject); ok {
+		for _, raw := range itemsOf(steps, "step") {
+			if _, err := f.addBuildTypeItem(bt, "steps", raw); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if templates, ok := body["templates"].(fakeObject); ok {
+		for _, raw := range itemsOf(templates, "buildType") {
+			if _, err := f.addBuildTypeItem(bt, "templates", raw); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return f.buildTypeReference(id), nil
+}
+
+func (f *fakeTeamCity) buildTypeReference(id string) fakeObject {
+	bt := f.buildTypes[id]
+	return fakeObject{
+		"id":        id,
+		"name":      bt.fields["name"],
+		"projectId": bt.fields["projectId"],
+		"href":      "/app/rest/buildTypes/id:" + id,
+		"webUrl":    fakeRootURL + "/viewType.html?buildTypeId=" + id,
+	}
+}
+
+func (f *fakeTeamCity) renderBuildType(id string) fakeObject {
+	bt := f.buildTypes[id]
+	out := f.buildTypeReference(id)
+	for _, k := range []string{"description", "templateFlag"} {
+		out[k] = bt.fields[k]
+	}
+	out["settings"] = fakeProperties(bt.fields["settings"])
+	out["parameters"] = fakeHidePasswordParameters(bt.fields["parameters"])
+	if p, ok := f.projects[stringField(bt.fields, "projectId")]; ok {
+		out["projectName"] = p["name"]
+	}
+	for name := range fakeCollections {
+		out[name] = f.renderCollection(bt, name)
+	}
+	return out
+}
+
+func (f *fakeTeamCity) renderCollection(bt *fakeBuildType, name string) fakeObject {
+	items := make([]interface{}, 0, len(bt.items[name]))
+	if name == "steps" {
+		for _, i := range f.inheritedSteps(bt) {
+			items = append(items, fakeRenderItem(i))
+		}
+	}
+	for _, i := range bt.items[name] {
+		items = append(items, fakeRenderItem(i))
+	}
+	return fakeObject{"count": len(items), fakeCollections[name].itemKey: items}
+}
+
+// inheritedSteps returns the steps of the templates of the build type, listed before its own steps and flagged as inherited.
+// Overriding them is not modelled.
+func (f *fakeTeamCity) inheritedSteps(bt *fakeBuildType) []fakeObject {
+	var out []fakeObject
+	for _, ref := range bt.items["templates"] {
+		template, ok := f.buildTypes[stringField(ref, "id")]
+		if !ok {
+			continue
+		}
+		for _, step := range template.items["steps"] {
+			inherited := fakeObject{"inherited": true}
+			for k, v := range step {
+				inherited[k] = v
+			}
+			out = append(out, inherited)
+		}
+	}
+	return out
+}
+
+// fakeRenderItem returns an item of a build type collection with the values of its secure properties removed
+func fakeRenderItem(item fakeObject) fakeObject {
+	if _, ok := item["properties"]; !ok {
+		return item
+	}
+	out := fakeObject{}
+	for k, v := range item {
+		out[k] = v
+	}
+	out["properties"] = fakeHideSecureValues(item["properties"])
+	return out
+}
+
+func itemsOf(collection fakeObject, itemKey string) []fakeObject {
+	raw, _ := collection[itemKey].([]interface{})
+	out := make([]fakeObject, 0, len(raw))
+	for _, i := range raw {
+		if o, ok := i.(fakeObject); ok {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// routeSettings serves build configuration settings, stored as properties. Settings are not removed when omitted from a PUT, only reset.
+func (f *fakeTeamCity) routeSettings(r *fakeRequest, bt *fakeBuildType, s []string) (interface{}, error) {
+	if len(s) == 0 {
+		if r.method == "PUT" {
+			body, err := r.decode()
+			if err != nil {
+				return nil, err
+			}
+			bt.fields["settings"] = fakeSettings(body)
+		}
+		return fakeProperties(bt.fields["settings"]), nil
+	}
+
+	name := s[0]
+	props := fakeProperties(bt.fields["settings"])
+	var value string
+	found := false
+	items := []interface{}{}
+	for _, raw := range props["property"].([]interface{}) {
+		p := raw.(fakeObject)
+		if p["name"] == name {
+			value, found = stringField(p, "value"), true
+			continue
+		}
+		items = append(items, p)
+	}
+	if r.method != "PUT" {
+		if !found {
+			return nil, fakeNotFound("No setting '%s'", name)
+		}
+		return value, nil
+	}
+	items = append(items, fakeObject{"name": name, "value": string(r.body)})
+	bt.fields["settings"] = fakeSettings(fakeObject{"property": items})
+	return string(r.body), nil
+}
+
+// fakeSettings drops settings reset to their empty default, which TeamCity does not list
+func fakeSettings(v interface{}) fakeObject {
+	items := []interface{}{}
+	for _, raw := range fakeProperties(v)["property"].([]interface{}) {
+		if p, ok := raw.(fakeObject); ok && stringField(p, "value") != "" {
+			items = append(items, p)
+		}
+	}
+	return fakeObject{"count": len(items), "property": items}
+}
+
+func (f *fakeTeamCity) routeBuildTypeCollection(r *fakeRequest, bt *fakeBuildType, name string, s []string) (interface{}, error) {
+	if len(s) == 0 {
+		switch r.method {
+		case "GET":
+			return f.renderCollection(bt, name), nil
+		case "POST":
+			body, err := r.decode()
+			if err != nil {
+				return nil, err
+			}
+			return f.addBuildTypeItem(bt, name, body)
+		case "PUT":
+			body, err := r.decode()
+			if err != nil {
+				return nil, err
+			}
+			items := itemsOf(body, fakeCollections[name].itemKey)
+			if name == "steps" {
+				// TeamCity turns inherited steps sent back into overrides, detaching them from the template
+				for _, inherited := range f.inheritedSteps(bt) {
+					for _, raw := range items {
+						if raw["id"] == inherited["id"] {
+							return nil, fakeBadRequest("step '%s' is inherited from a template, overriding it is not supported", raw["id"])
+						}
+					}
+				}
+			}
+			bt.items[name] = nil
+			for _, raw := range items {
+				if _, err := f.addBuildTypeItem(bt, name, raw); err != nil {
+					return nil, err
+				}
+			}
+			return f.renderCollection(bt, name), nil
+		}
+		return nil, fakeBadRequest("unsupported %s on %s", r.method, name)
+	}
+
+	id := strings.TrimPrefix(s[0], "id:")
+	if name == "steps" && r.method != "GET" {
+		for _, inherited := range f.inheritedSteps(bt) {
+			if inherited["id"] == id {
+				return nil, fakeBadRequest("step '%s' is inherited from a template, overriding it is not supported", id)
+			}
+		}
+	}
+	for i, item := range bt.items[name] {
+		if item["id"] != id {
+			continue
+		}
+		switch r.method {
+		case "GET":
+			return fakeRenderItem(item), nil
+		case "DELETE":
+			bt.items[name] = append(bt.items[name][:i], bt.items[name][i+1:]...)
+			return nil, nil
+		case "PUT":
+			body, err := r.decode()
+			if err != nil {
+				return nil, err
+			}
+			body["id"] = id
+			bt.items[name][i] = body
+			return body, nil
+		}
+		return nil, fakeBadRequest("unsupported %s on %s item", r.method, name)
+	}
+	return nil, fakeNotFound("No %s item with id '%s' found in build type '%s'", name, id, bt.fields["id"])
+}
+
+// addBuildTypeItem stores an item in a collection of the build type, assigning its ID the way TeamCity does
+func (f *fakeTeamCity) addBuildTypeItem(bt *fakeBuildType, name string, item fakeObject) (fakeObject, error) {
+	// TeamCity only sends these flags when they are set
+	for _, flag := range []string{"disabled", "inherited"} {
+		if v, ok := item[flag].(bool); ok && !v {
+			delete(item, flag)
+		}
+	}
+
+	switch name {
+	case "templates":
+		id, ok := f.locateBuildType(stringField(item, "id"))
+		if !ok {
+			return nil, fakeNotFound("No build type or template is found by id '%s'", stringField(item, "id"))
+		}
+		for _, existing := range bt.items[name] {
+			if existing["id"] == id {
+				return existing, nil
+			}
+		}
+		item = f.buildTypeReference(id)
+	case "vcs-root-entries":
+		id, ok := locate(stringField(item, "vcs-root", "id"), f.vcsRoots)
+		if !ok {
+			return nil, fakeNotFound("No VCS root found by locator '%s'", stringField(item, "vcs-root", "id"))
+		}
+		for _, existing := range bt.items[name] {
+			if existing["id"] == id {
+				return nil, fakeBadRequest("VCS root '%s' is already attached", id)
+			}
+		}
+		root := f.vcsRoots[id]
+		item["id"] = id
+		item["vcs-root"] = fakeObject{"id": id, "name": root["name"], "href": "/app/rest/vcs-roots/id:" + id}
+	case "snapshot-dependencies":
+		id, ok := f.locateBuildType(stringField(item, "source-buildType", "id"))
+		if !ok {
+			return nil, fakeNotFound("No build type found by id '%s'", stringField(item, "source-buildType", "id"))
+		}
+		item["id"] = id
+		item["source-buildType"] = f.buildTypeReference(id)
+	case "artifact-dependencies":
+		id, ok := f.locateBuildType(stringField(item, "source-buildType", "id"))
+		if !ok {
+			return nil, fakeNotFound("No build type found by id '%s'", stringField(item, "source-buildType", "id"))
+		}
+		item["source-buildType"] = f.buildTypeReference(id)
+		item["id"] = f.nextID(fakeCollections[name].idPrefix)
+	default:
+		if stringField(item, "id") == "" {
+			item["id"] = f.nextID(fakeCollections[name].idPrefix)
+		}
+	}
+
+	bt.items[name] = append(bt.items[name], item)
+	return item, nil
+}
@@ -0,0 +1,863 @@
+package teamcity_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// fakeServerEnvVar selects the in-process fake TeamCity server for acceptance tests, instead of the server at TEAMCITY_ADDR
+const fakeServerEnvVar = "TEAMCITY_FAKE_SERVER"
+
+// fakeRootURL is the server URL the fake reports in webUrl attributes, matching the docker-compose server from integration_tests
+const fakeRootURL = "http://127.0.0.1:8112"
+
+type fakeObject = map[string]interface{}
+
+// fakeTeamCity is an in-process stand-in for the TeamCity REST API, so acceptance tests can run without a server.
+// It keeps the JSON documents sent by the provider, assigns IDs the way TeamCity does, and serves them back
+// in the shape go-teamcity reads. Only the endpoints used by the provider are modelled, with little validation.
+type fakeTeamCity struct {
+	mu sync.Mutex
+
+	projects   map[string]fakeObject
+	buildTypes map[string]*fakeBuildType
+	vcsRoots   map[string]fakeObject
+	groups     map[string]fakeObject
+	counter    int
+}
+
+// fakeBuildType holds a build configuration or template, with the collections nested under it
+type fakeBuildType struct {
+	fields fakeObject
+	items  map[string][]fakeObject
+}
+
+// fakeCollections maps the collections nested under a build configuration to the key holding their items,
+// and to the prefix of the IDs TeamCity generates for them
+var fakeCollections = map[string]struct{ itemKey, idPrefix string }{
+	"steps":                 {"step", "RUNNER_"},
+	"triggers":              {"trigger", "TRIGGER_"},
+	"features":              {"feature", "BUILD_EXT_"},
+	"agent-requirements":    {"agent-requirement", "RQ_"},
+	"artifact-dependencies": {"artifact-dependency", "ARTIFACT_DEPENDENCY_"},
+	"snapshot-dependencies": {"snapshot-dependency", ""},
+	"vcs-root-entries":      {"vcs-root-entry", ""},
+	"templates":             {"buildType", ""},
+}
+
+func newFakeTeamCity() *fakeTeamCity {
+	return &fakeTeamCity{
+		projects: map[string]fakeObject{
+			"_Root": {"id": "_Root", "name": "<Root project>", "description": "Contains all other projects"},
+		},
+		buildTypes: map[string]*fakeBuildType{},
+		vcsRoots:   map[string]fakeObject{},
+		groups:     map[string]fakeObject{},
+	}
+}
+
+// fakeError is written as a TeamCity error response
+type fakeError struct {
+	status  int
+	message string
+}
+
+func (e *fakeError) Error() string {
+	return e.message
+}
+
+func fakeNotFound(format string, args ...interface{}) *fakeError {
+	return &fakeError{status: http.StatusNotFound, message: fmt.Sprintf(format, args...)}
+}
+
+func fakeBadRequest(format string, args ...interface{}) *fakeError {
+	return &fakeError{status: http.StatusBadRequest, message: fmt.Sprintf(format, args...)}
+}
+
+// fakeRequest is a REST call, with the path split in segments relative to the REST API root
+type fakeRequest struct {
+	method      string
+	segments    []string
+	query       url.Values
+	contentType string
+	body        []byte
+}
+
+func (r *fakeRequest) decode() (fakeObject, error) {
+	var out fakeObject
+	if err := json.Unmarshal(r.body, &out); err != nil {
+		return nil, fakeBadRequest("invalid JSON body: %s", err)
+	}
+	return out, nil
+}
+
+// formFile returns the content of the file uploaded in the given field of a multipart/form-data body
+func (r *fakeRequest) formFile(field string) ([]byte, error) {
+	mediaType, params, err := mime.ParseMediaType(r.contentType)
+	if err != nil || mediaType != "multipart/form-data" {
+		return nil, fakeBadRequest("multipart/form-data body expected, got '%s'", r.contentType)
+	}
+	form, err := multipart.NewReader(bytes.NewReader(r.body), params["boundary"]).ReadForm(1 << 20)
+	if err != nil {
+		return nil, fakeBadRequest("invalid multipart body: %s", err)
+	}
+	files := form.File[field]
+	if len(files) == 0 {
+		return nil, fakeBadRequest("file '%s' is required", field)
+	}
+	file, err := files[0].Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ioutil.ReadAll(file)
+}
+
+func (f *fakeTeamCity) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.EscapedPath()
+	for _, prefix := range []string{"/httpAuth/app/rest/", "/app/rest/"} {
+		if strings.HasPrefix(path, prefix) {
+			path = strings.TrimPrefix(path, prefix)
+			break
+		}
+	}
+
+	var segments []string
+	for _, s := range strings.Split(strings.Trim(path, "/"), "/") {
+		if s == "" {
+			continue
+		}
+		unescaped, err := url.PathUnescape(s)
+		if err != nil {
+			unescaped = s
+		}
+		segments = append(segments, unescaped)
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	req := &fakeRequest{method: r.Method, segments: segments, query: r.URL.Query(), contentType: r.Header.Get("Content-Type"), body: body}
+
+	f.mu.Lock()
+	out, err := f.route(req)
+	f.mu.Unlock()
+
+	if err != nil {
+		status := http.StatusInternalServerError
+		if e, ok := err.(*fakeError); ok {
+			status = e.status
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		fmt.Fprintf(w, "Responding with error, status code: %d (%s).\nDetails: %s", status, http.StatusText(status), err)
+		return
+	}
+
+	switch v := out.(type) {
+	case nil:
+		w.WriteHeader(http.StatusNoContent)
+	case string:
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(v))
+	default:
+		dt, _ := json.Marshal(v)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(dt)
+	}
+}
+
+func (f *fakeTeamCity) route(r *fakeRequest) (interface{}, error) {
+	if len(r.segments) == 0 {
+		return nil, fakeNotFound("no resource")
+	}
+
+	switch r.segments[0] {
+	case "server":
+		return fakeObject{
+			"version":      "2020.1.3 (build 78938)",
+			"versionMajor": 2020,
+			"versionMinor": 1,
+			"buildNumber":  "78938",
+			"webUrl":       fakeRootURL,
+		}, nil
+	case "projects":
+		return f.routeProjects(r)
+	case "buildTypes":
+		return f.routeBuildTypes(r)
+	case "vcs-roots":
+		return f.routeVcsRoots(r)
+	case "userGroups":
+		return f.routeGroups(r)
+	}
+	return nil, fakeNotFound("unsupported resource '%s'", strings.Join(r.segments, "/"))
+}
+
+// locate resolves a locator such as "id:X", "name:X" or a plain ID against objects keyed by ID
+func locate(locator string, objects map[string]fakeObject) (string, bool) {
+	switch {
+	case strings.HasPrefix(locator, "id:"):
+		locator = strings.TrimPrefix(locator, "id:")
+	case strings.HasPrefix(locator, "name:"):
+		name := strings.TrimPrefix(locator, "name:")
+		for id, o := range objects {
+			if o["name"] == name {
+				return id, true
+			}
+		}
+		return "", false
+	case strings.HasPrefix(locator, "key:"):
+		locator = strings.TrimPrefix(locator, "key:")
+	}
+	_, ok := objects[locator]
+	return locator, ok
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// generateID builds an ID from a name like TeamCity does, e.g. "Build release" under project "Parent" becomes "Parent_BuildRelease"
+func (f *fakeTeamCity) generateID(parentID string, name string, exists func(string) bool) string {
+	var b strings.Builder
+	for _, word := range nonAlphanumeric.Split(name, -1) {
+		if word == "" {
+			continue
+		}
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+
+	id := b.String()
+	if id == "" || unicode.IsDigit([]rune(id)[0]) {
+		id = "Id" + id
+	}
+	if parentID != "" && parentID != "_Root" {
+		id = parentID + "_" + id
+	}
+
+	out := id
+	for i := 2; exists(out); i++ {
+		out = fmt.Sprintf("%s%d", id, i)
+	}
+	return out
+}
+
+func (f *fakeTeamCity) nextID(prefix string) string {
+	f.counter++
+	return fmt.Sprintf("%s%d", prefix, f.counter)
+}
+
+func fakeProperties(v interface{}) fakeObject {
+	props, _ := v.(fakeObject)
+	items, _ := props["property"].([]interface{})
+	if items == nil {
+		items = []interface{}{}
+	}
+	return fakeObject{"count": len(items), "property": items}
+}
+
+// fakeHideSecureValues drops the values of secure properties, which TeamCity never returns
+func fakeHideSecureValues(v interface{}) fakeObject {
+	items := []interface{}{}
+	for _, raw := range fakeProperties(v)["property"].([]interface{}) {
+		p, ok := raw.(fakeObject)
+		if ok && strings.HasPrefix(stringField(p, "name"), "secure:") {
+			p = fakeObject{"name": p["name"]}
+		}
+		items = append(items, p)
+	}
+	return fakeObject{"count": len(items), "property": items}
+}
+
+func stringField(o fakeObject, keys ...string) string {
+	var v interface{} = o
+	for _, k := range keys {
+		m, ok := v.(fakeObject)
+		if !ok {
+			return ""
+		}
+		v = m[k]
+	}
+	s, _ := v.(string)
+	return s
+}
+
+// --- projects
+
+func (f *fakeTeamCity) routeProjects(r *fakeRequest) (interface{}, error) {
+	s := r.segments
+	if len(s) == 1 {
+		if r.method == "POST" {
+			return f.createProject(r)
+		}
+		return nil, fakeBadRequest("unsupported %s on projects", r.method)
+	}
+
+	id, ok := locate(s[1], f.projects)
+	if !ok {
+		return nil, fakeNotFound("No project found by locator '%s'", s[1])
+	}
+	project := f.projects[id]
+
+	if len(s) == 2 {
+		switch r.method {
+		case "GET":
+			return f.renderProject(id), nil
+		case "DELETE":
+			f.deleteProject(id)
+			return nil, nil
+		}
+		return nil, fakeBadRequest("unsupported %s on project", r.method)
+	}
+
+	switch s[2] {
+	case "name", "description":
+		if r.method == "PUT" {
+			project[s[2]] = string(r.body)
+		}
+		return stringField(project, s[2]), nil
+	case "parentProject":
+		if r.method == "PUT" {
+			body, err := r.decode()
+			if err != nil {
+				return nil, err
+			}
+			parent, ok := locate(stringField(body, "id"), f.projects)
+			if !ok {
+				return nil, fakeNotFound("No project found by locator '%s'", stringField(body, "id"))
+			}
+			project["parentProjectId"] = parent
+		}
+		return f.projectReference(stringField(project, "parentProjectId")), nil
+	case "parameters":
+		return f.routeParameters(r, project, s[3:])
+	case "sshKeys":
+		return f.routeSshKeys(r, project)
+	case "projectFeatures":
+		return f.routeProjectFeatures(r, project, s[3:])
+	}
+	return nil, fakeNotFound("unsupported project resource '%s'", s[2])
+}
+
+// routeSshKeys serves the SSH keys uploaded into a project. Like TeamCity, private keys are never returned,
+// and the public key is only derived from unencrypted PKCS#8 Ed25519 keys, which are the ones the tests upload.
+func (f *fakeTeamCity) routeSshKeys(r *fakeRequest, project fakeObject) (interface{}, error) {
+	keys, _ := project["sshKeys"].([]interface{})
+
+	switch r.method {
+	case "GET":
+		return fakeObject{"sshKey": keys}, nil
+	case "POST":
+		name := r.query.Get("fileName")
+		if name == "" {
+			return nil, fakeBadRequest("fileName is required")
+		}
+		for _, k := range keys {
+			if k.(fakeObject)["name"] == name {
+				return nil, fakeBadRequest("SSH key with name '%s' already exists", name)
+			}
+		}
+		privateKey, err := r.formFile("fileToUpload")
+		if err != nil {
+			return nil, err
+		}
+		key := fakeObject{"name": name, "encrypted": strings.Contains(string(privateKey), "ENCRYPTED")}
+		if publicKey := fakeSshPublicKey(privateKey); publicKey != "" {
+			key["publicKey"] = publicKey
+		}
+		project["sshKeys"] = append(keys, key)
+		return nil, nil
+	case "DELETE":
+		name := r.query.Get("fileName")
+		for i, k := range keys {
+			if k.(fakeObject)["name"] == name {
+				project["sshKeys"] = append(keys[:i:i], keys[i+1:]...)
+				return nil, nil
+			}
+		}
+		return nil, fakeNotFound("No SSH key found by name '%s'", name)
+	}
+	return nil, fakeBadRequest("unsupported %s on sshKeys", r.method)
+}
+
+// routeProjectFeatures serves the features of a project, e.g. connections, stored under the "projectFeatures" key of project
+func (f *fakeTeamCity) routeProjectFeatures(r *fakeRequest, project fakeObject, s []string) (interface{}, error) {
+	features, _ := project["projectFeatures"].([]interface{})
+	render := func(feature fakeObject) fakeObject {
+		return fakeObject{"id": feature["id"], "type": feature["type"], "properties": fakeHideSecureValues(feature["properties"])}
+	}
+
+	if len(s) == 0 {
+		switch r.method {
+		case "GET":
+			items := []interface{}{}
+			for _, feature := range features {
+				items = append(items, render(feature.(fakeObject)))
+			}
+			return fakeObject{"count": len(items), "projectFeature": items}, nil
+		case "POST":
+			body, err := r.decode()
+			if err != nil {
+				return nil, err
+			}
+			if stringField(body, "type") == "" {
+				return nil, fakeBadRequest("project feature type is required")
+			}
+			feature := fakeObject{"id": f.nextID("PROJECT_EXT_"), "type": body["type"], "properties": fakeProperties(body["properties"])}
+			project["projectFeatures"] = append(features, feature)
+			return render(feature), nil
+		}
+		return nil, fakeBadRequest("unsupported %s on projectFeatures", r.method)
+	}
+
+	for i, raw := range features {
+		feature := raw.(fakeObject)
+		if feature["id"] != strings.TrimPrefix(s[0], "id:") {
+			continue
+		}
+
+		switch {
+		case len(s) == 1 && r.method == "GET":
+			return render(feature), nil
+		case len(s) == 1 && r.method == "DELETE":
+			project["projectFeatures"] = append(features[:i:i], features[i+1:]...)
+			return nil, nil
+		case len(s) == 2 && s[1] == "properties" && r.method == "PUT":
+			body, err := r.decode()
+			if err != nil {
+				return nil, err
+			}
+			feature["properties"] = fakeProperties(body)
+			return fakeHideSecureValues(feature["properties"]), nil
+		}
+		return nil, fakeBadRequest("unsupported %s on project feature", r.method)
+	}
+	return nil, fakeNotFound("No project feature found by locator '%s'", s[0])
+}
+
+func fakeSshPublicKey(privateKey []byte) string {
+	block, _ := pem.Decode(privateKey)
+	if block == nil {
+		return ""
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return ""
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return ""
+	}
+
+	var blob []byte
+	for _, field := range [][]byte{[]byte("ssh-ed25519"), edKey.Public().(ed25519.PublicKey)} {
+		blob = append(blob, byte(len(field)>>24), byte(len(field)>>16), byte(len(field)>>8), byte(len(field)))
+		blob = append(blob, field...)
+	}
+	return "ssh-ed25519 " + base64.StdEncoding.EncodeToString(blob)
+}
+
+func (f *fakeTeamCity) createProject(r *fakeRequest) (interface{}, error) {
+	body, err := r.decode()
+	if err != nil {
+		return nil, err
+	}
+	name := stringField(body, "name")
+	if name == "" {
+		return nil, fakeBadRequest("Project name cannot be empty")
+	}
+
+	parentID := stringField(body, "parentProject", "id")
+	if parentID == "" {
+		parentID = stringField(body, "parentProjectId")
+	}
+	if parentID == "" {
+		parentID = "_Root"
+	}
+	if _, ok := f.projects[parentID]; !ok {
+		return nil, fakeNotFound("No project found by locator 'id:%s'", parentID)
+	}
+	for _, p := range f.projects {
+		if p["parentProjectId"] == parentID && p["name"] == name {
+			return nil, fakeBadRequest("Project with this name already exists: %s", name)
+		}
+	}
+
+	id := stringField(body, "id")
+	if id == "" {
+		id = f.generateID(parentID, name, func(id string) bool { _, ok := f.projects[id]; return ok })
+	}
+
+	f.projects[id] = fakeObject{
+		"id":              id,
+		"name":            name,
+		"description":     stringField(body, "description"),
+		"parentProjectId": parentID,
+		"parameters":      fakeProperties(body["parameters"]),
+	}
+	return f.projectReference(id), nil
+}
+
+func (f *fakeTeamCity) projectReference(id string) fakeObject {
+	p, ok := f.projects[id]
+	if !ok {
+		return nil
+	}
+	return fakeObject{
+		"id":     id,
+		"name":   p["name"],
+		"href":   "/app/rest/projects/id:" + id,
+		"webUrl": fmt.Sprintf("%s/project.html?projectId=%s", fakeRootURL, id),
+	}
+}
+
+func (f *fakeTeamCity) renderProject(id string) fakeObject {
+	p := f.projects[id]
+	out := f.projectReference(id)
+	out["description"] = p["description"]
+	out["parameters"] = fakeHidePasswordParameters(p["parameters"])
+
+	if parentID := stringField(p, "parentProjectId"); parentID != "" {
+		out["parentProjectId"] = parentID
+		out["parentProject"] = f.projectReference(parentID)
+	}
+
+	var children []interface{}
+	for _, childID := range sortedKeys(f.projects) {
+		if f.projects[childID]["parentProjectId"] == id {
+			children = append(children, f.projectReference(childID))
+		}
+	}
+	out["projects"] = fakeObject{"count": len(children), "project": children}
+
+	var buildTypes []interface{}
+	for _, btID := range sortedBuildTypeKeys(f.buildTypes) {
+		if f.buildTypes[btID].fields["projectId"] == id {
+			buildTypes = append(buildTypes, f.buildTypeReference(btID))
+		}
+	}
+	out["buildTypes"] = fakeObject{"count": len(buildTypes), "buildType": buildTypes}
+	return out
+}
+
+func (f *fakeTeamCity) deleteProject(id string) {
+	for childID, p := range f.projects {
+		if p["parentProjectId"] == id {
+			f.deleteProject(childID)
+		}
+	}
+	for btID, bt := range f.buildTypes {
+		if bt.fields["projectId"] == id {
+			delete(f.buildTypes, btID)
+		}
+	}
+	for vcsID, v := range f.vcsRoots {
+		if stringField(v, "project", "id") == id {
+			delete(f.vcsRoots, vcsID)
+		}
+	}
+	delete(f.projects, id)
+}
+
+// routeParameters serves a parameter collection, stored as properties under the "parameters" key of owner.
+// Like TeamCity, values of password parameters are never returned.
+func (f *fakeTeamCity) routeParameters(r *fakeRequest, owner fakeObject, s []string) (interface{}, error) {
+	if len(s) == 0 {
+		switch r.method {
+		case "GET":
+			return fakeHidePasswordParameters(owner["parameters"]), nil
+		case "PUT":
+			body, err := r.decode()
+			if err != nil {
+				return nil, err
+			}
+			owner["parameters"] = fakeProperties(body)
+			return fakeHidePasswordParameters(owner["parameters"]), nil
+		}
+		return nil, fakeBadRequest("unsupported %s on parameters", r.method)
+	}
+
+	items := fakeProperties(owner["parameters"])["property"].([]interface{})
+	index := -1
+	for i, raw := range items {
+		if stringField(raw.(fakeObject), "name") == s[0] {
+			index = i
+		}
+	}
+	save := func(p fakeObject) {
+		if index < 0 {
+			items = append(items, p)
+		} else {
+			items[index] = p
+		}
+		owner["parameters"] = fakeObject{"property": items}
+	}
+
+	switch {
+	case len(s) == 1 && r.method == "PUT":
+		body, err := r.decode()
+		if err != nil {
+			return nil, err
+		}
+		p := fakeObject{"name": s[0], "value": stringField(body, "value")}
+		if t, ok := body["type"].(fakeObject); ok && stringField(t, "rawValue") != "" {
+			p["type"] = fakeObject{"rawValue": stringField(t, "rawValue")}
+		}
+		save(p)
+		return fakeHidePasswordParameter(p), nil
+	case len(s) == 2 && s[1] == "value" && r.method == "PUT":
+		p := fakeObject{"name": s[0]}
+		if index >= 0 {
+			for k, v := range items[index].(fakeObject) {
+				p[k] = v
+			}
+		}
+		p["value"] = string(r.body)
+		save(p)
+		return string(r.body), nil
+	}
+
+	if index < 0 {
+		return nil, fakeNotFound("No parameter with name '%s' is found", s[0])
+	}
+	switch {
+	case len(s) == 1 && r.method == "GET":
+		return fakeHidePasswordParameter(items[index].(fakeObject)), nil
+	case len(s) == 1 && r.method == "DELETE":
+		owner["parameters"] = fakeObject{"property": append(items[:index:index], items[index+1:]...)}
+		return nil, nil
+	}
+	return nil, fakeBadRequest("unsupported %s on parameter", r.method)
+}
+
+// storedParameterValue returns the value stored for a parameter of a project or build configuration,
+// including the values of password parameters the API never returns
+func (f *fakeTeamCity) storedParameterValue(ownerID string, name string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	owner, ok := f.projects[ownerID]
+	if !ok {
+		bt, ok := f.buildTypes[ownerID]
+		if !ok {
+			return "", false
+		}
+		owner = bt.fields
+	}
+	for _, raw := range fakeProperties(owner["parameters"])["property"].([]interface{}) {
+		if p := raw.(fakeObject); stringField(p, "name") == name {
+			return stringField(p, "value"), true
+		}
+	}
+	return "", false
+}
+
+// setStoredParameterValue changes the value of a parameter of a project or build configuration, as if it was edited in the UI
+func (f *fakeTeamCity) setStoredParameterValue(ownerID string, name string, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	owner, ok := f.projects[ownerID]
+	if !ok {
+		owner = f.buildTypes[ownerID].fields
+	}
+	for _, raw := range fakeProperties(owner["parameters"])["property"].([]interface{}) {
+		if p := raw.(fakeObject); stringField(p, "name") == name {
+			p["value"] = value
+		}
+	}
+}
+
+func fakeHidePasswordParameters(v interface{}) fakeObject {
+	items := []interface{}{}
+	for _, raw := range fakeProperties(v)["property"].([]interface{}) {
+		items = append(items, fakeHidePasswordParameter(raw.(fakeObject)))
+	}
+	return fakeObject{"count": len(items), "property": items}
+}
+
+func fakeHidePasswordParameter(p fakeObject) fakeObject {
+	if !strings.HasPrefix(stringField(p, "type", "rawValue"), "password") {
+		return p
+	}
+	out := fakeObject{}
+	for k, v := range p {
+		out[k] = v
+	}
+	out["value"] = ""
+	return out
+}
+
+// --- user groups
+
+func (f *fakeTeamCity) routeGroups(r *fakeRequest) (interface{}, error) {
+	s := r.segments
+	if len(s) == 1 {
+		if r.method != "POST" {
+			return nil, fakeBadRequest("unsupported %s on userGroups", r.method)
+		}
+		body, err := r.decode()
+		if err != nil {
+			return nil, err
+		}
+		key := stringField(body, "key")
+		if key == "" || stringField(body, "name") == "" {
+			return nil, fakeBadRequest("Group key and name are required")
+		}
+		if _, ok := f.groups[key]; ok {
+			return nil, fakeBadRequest("Group with key '%s' already exists", key)
+		}
+		f.groups[key] = fakeObject{"key": key, "name": body["name"], "description": body["description"]}
+		return f.groups[key], nil
+	}
+
+	key, ok := locate(s[1], f.groups)
+	if !ok {
+		return nil, fakeNotFound("No group found by locator '%s'", s[1])
+	}
+	if len(s) == 2 {
+		switch r.method {
+		case "GET":
+			return f.groups[key], nil
+		case "DELETE":
+			delete(f.groups, key)
+			return nil, nil
+		}
+	}
+	return nil, fakeBadRequest("unsupported %s on group", r.method)
+}
+
+// --- VCS roots
+
+func (f *fakeTeamCity) routeVcsRoots(r *fakeRequest) (interface{}, error) {
+	s := r.segments
+	if len(s) == 1 {
+		if r.method == "POST" {
+			return f.createVcsRoot(r)
+		}
+		return nil, fakeBadRequest("unsupported %s on vcs-roots", r.method)
+	}
+
+	id, ok := locate(s[1], f.vcsRoots)
+	if !ok {
+		return nil, fakeNotFound("No VCS root found by locator '%s'", s[1])
+	}
+	root := f.vcsRoots[id]
+
+	if len(s) == 2 {
+		switch r.method {
+		case "GET":
+			out := fakeObject{}
+			for k, v := range root {
+				out[k] = v
+			}
+			out["properties"] = fakeHideSecureValues(root["properties"])
+			return out, nil
+		case "DELETE":
+			delete(f.vcsRoots, id)
+			return nil, nil
+		}
+		return nil, fakeBadRequest("unsupported %s on VCS root", r.method)
+	}
+
+	switch s[2] {
+	case "name":
+		if r.method == "PUT" {
+			root["name"] = string(r.body)
+		}
+		return stringField(root, "name"), nil
+	case "projectId":
+		if r.method == "PUT" {
+			projectID, ok := locate(string(r.body), f.projects)
+			if !ok {
+				return nil, fakeNotFound("No project found by locator '%s'", string(r.body))
+			}
+			root["project"] = f.projectReference(projectID)
+		}
+		return stringField(root, "project", "id"), nil
+	case "modificationCheckInterval":
+		if r.method == "PUT" {
+			var v int
+			if _, err := fmt.Sscanf(string(r.body), "%d", &v); err != nil {
+				return nil, fakeBadRequest("invalid modificationCheckInterval '%s'", string(r.body))
+			}
+			root["modificationCheckInterval"] = v
+		}
+		return fmt.Sprintf("%v", root["modificationCheckInterval"]), nil
+	case "properties":
+		if r.method == "PUT" {
+			body, err := r.decode()
+			if err != nil {
+				return nil, err
+			}
+			root["properties"] = fakeProperties(body)
+		}
+		return fakeHideSecureValues(root["properties"]), nil
+	}
+	return nil, fakeNotFound("unsupported VCS root resource '%s'", s[2])
+}
+
+func (f *fakeTeamCity) createVcsRoot(r *fakeRequest) (interface{}, error) {
+	body, err := r.decode()
+	if err != nil {
+		return nil, err
+	}
+	name := stringField(body, "name")
+	if name == "" || stringField(body, "vcsName") == "" {
+		return nil, fakeBadRequest("VCS root name and vcsName are required")
+	}
+	projectID, ok := locate(stringField(body, "project", "id"), f.projects)
+	if !ok {
+		return nil, fakeNotFound("No project found by locator 'id:%s'", stringField(body, "project", "id"))
+	}
+
+	id := stringField(body, "id")
+	if id == "" {
+		id = f.generateID(projectID, name, func(id string) bool { _, ok := f.vcsRoots[id]; return ok })
+	}
+
+	root := fakeObject{
+		"id":         id,
+		"name":       name,
+		"vcsName":    body["vcsName"],
+		"project":    f.projectReference(projectID),
+		"properties": fakeProperties(body["properties"]),
+	}
+	if v, ok := body["modificationCheckInterval"]; ok {
+		root["modificationCheckInterval"] = v
+	}
+	f.vcsRoots[id] = root
+
+	return fakeObject{"id": id, "name": name, "href": "/app/rest/vcs-roots/id:" + id, "project": root["project"]}, nil
+}
+
+func sortedKeys(m map[string]fakeObject) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func sortedBuildTypeKeys(m map[string]*fakeBuildType) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
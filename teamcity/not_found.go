@@ -0,0 +1,69 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	api "github.com/leidruid/go-teamcity/teamcity"
+)
+
+// apiError is returned by Client.doRequest when TeamCity answers with an unsuccessful status
+type apiError struct {
+	StatusCode          int
+	Method              string
+	ResourceDescription string
+	Body                string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("Error '%d' when performing '%s' operation - %s: %s", e.StatusCode, e.Method, e.ResourceDescription, e.Body)
+}
+
+// isNotFound reports whether err was caused by TeamCity answering 404 Not Found
+func isNotFound(err error) bool {
+	var e *apiError
+	return errors.As(err, &e) && e.StatusCode == http.StatusNotFound
+}
+
+// handleNotFound is called by Read functions when retrieving the object failed with readErr.
+// go-teamcity does not report status codes, so the object is looked up at path: when it no longer exists,
+// e.g. because it was deleted outside Terraform, it is removed from state. Otherwise readErr is returned.
+func handleNotFound(d *schema.ResourceData, client *Client, path string, readErr error) error {
+	if d.IsNewResource() {
+		return readErr
+	}
+
+	err := client.doRequest("GET", path, nil, nil, path)
+	if !isNotFound(err) {
+		return readErr
+	}
+
+	log.Printf("[WARN] %s not found, removing '%s' from state", path, d.Id())
+	d.SetId("")
+	return nil
+}
+
+func projectPath(id string) string {
+	return fmt.Sprintf("projects/%s", api.LocatorID(id))
+}
+
+func buildTypePath(id string) string {
+	return fmt.Sprintf("buildTypes/%s", api.LocatorID(id))
+}
+
+// buildTypeItemPath addresses an item nested under a build configuration, e.g. a trigger or a feature
+func buildTypeItemPath(buildTypeID string, collection string, id string) string {
+	return fmt.Sprintf("%s/%s/%s", buildTypePath(buildTypeID), collection, url.PathEscape(id))
+}
+
+func vcsRootPath(id string) string {
+	return fmt.Sprintf("vcs-roots/%s", api.LocatorID(id))
+}
+
+func groupPath(key string) string {
+	return fmt.Sprintf("userGroups/%s", api.LocatorKey(key))
+}
@@ -0,0 +1,58 @@
+package teamcity_test
+
+import (
+	"net/http"
+	"testing"
+)
+
+// compositeIDs holds valid IDs of resources whose ID embeds the ID of their parent
+var compositeIDs = map[string]string{
+	"teamcity_project_ssh_key":        "Project1/Deleted",
+	"teamcity_project_parameter":      "Project1/Deleted",
+	"teamcity_build_config_parameter": "Project1_Build/Deleted",
+	"teamcity_build_step":             "Project1_Build/Deleted",
+}
+
+func TestRead_RemovesObjectsDeletedOutsideTerraform(t *testing.T) {
+	p := testProviderWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("Not found"))
+	}))
+
+	for name, r := range p.ResourcesMap {
+		d := r.TestResourceData()
+		d.SetId("Deleted")
+		if id, ok := compositeIDs[name]; ok {
+			d.SetId(id)
+		}
+		if _, ok := r.Schema["build_config_id"]; ok {
+			d.Set("build_config_id", "Project1_Build")
+		}
+
+		if err := r.Read(d, p.Meta()); err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+			continue
+		}
+		if d.Id() != "" {
+			t.Errorf("%s: expected the resource to be removed from state", name)
+		}
+	}
+}
+
+func TestRead_KeepsObjectsOnOtherErrors(t *testing.T) {
+	p := testProviderWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("Access denied"))
+	}))
+
+	r := p.ResourcesMap["teamcity_project"]
+	d := r.TestResourceData()
+	d.SetId("Project1")
+
+	if err := r.Read(d, p.Meta()); err == nil {
+		t.Fatalf("expected the read error to be returned")
+	}
+	if d.Id() != "Project1" {
+		t.Fatalf("expected the resource to be kept in state, got ID %q", d.Id())
+	}
+}
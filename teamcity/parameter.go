@@ -0,0 +1,666 @@
+package teamcity
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// parameter is a project or build configuration parameter, as returned by the {owner}/parameters endpoint.
+// go-teamcity drops the type of parameters, which holds their spec, so they are read and written with raw REST calls.
+type parameter struct {
+	Name      string         `json:"name"`
+	Value     string         `json:"value"`
+	Inherited bool           `json:"inherited,omitempty"`
+	Type      *parameterType `json:"type,omitempty"`
+}
+
+type parameterType struct {
+	RawValue string `json:"rawValue"`
+}
+
+type parameterList struct {
+	Items []*parameter `json:"property"`
+}
+
+// parameterPrefixes maps the parameter maps of projects and build configurations to the prefix of their parameter names
+var parameterPrefixes = map[string]string{
+	"config_params": "",
+	"sys_params":    "system.",
+	"env_params":    "env.",
+}
+
+func parameterPath(ownerPath string, name string) string {
+	return fmt.Sprintf("%s/parameters/%s", ownerPath, url.PathEscape(name))
+}
+
+func (c *Client) getParameters(ownerPath string) ([]*parameter, error) {
+	var out parameterList
+	if err := c.doRequest("GET", ownerPath+"/parameters", nil, &out, "parameters"); err != nil {
+		return nil, err
+	}
+	return out.Items, nil
+}
+
+func (c *Client) getParameter(ownerPath string, name string) (*parameter, error) {
+	var out parameter
+	if err := c.doRequest("GET", parameterPath(ownerPath, name), nil, &out, "parameter"); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) putParameter(ownerPath string, dt *parameter) error {
+	return c.doRequest("PUT", parameterPath(ownerPath, dt.Name), dt, nil, "parameter")
+}
+
+// putParameterValue sets the value of a parameter, keeping its spec
+func (c *Client) putParameterValue(ownerPath string, name string, value string) error {
+	return c.doRequest("PUT", parameterPath(ownerPath, name)+"/value", value, nil, "parameter value")
+}
+
+func (c *Client) deleteParameter(ownerPath string, name string) error {
+	return c.doRequest("DELETE", parameterPath(ownerPath, name), nil, nil, "parameter")
+}
+
+// updateParameterCollection applies the changes of the parameter maps of a project or build configuration one parameter at a time,
+// so that parameters not declared in the maps, e.g. managed by teamcity_project_parameter, are left untouched
+func updateParameterCollection(d *schema.ResourceData, client *Client, ownerPath string) error {
+	for key, prefix := range parameterPrefixes {
+		if !d.HasChange(key) {
+			continue
+		}
+		o, n := d.GetChange(key)
+		old, new := o.(map[string]interface{}), n.(map[string]interface{})
+
+		for name := range old {
+			if _, ok := new[name]; ok {
+				continue
+			}
+			if err := client.deleteParameter(ownerPath, prefix+name); err != nil && !isNotFound(err) {
+				return err
+			}
+		}
+		for name, v := range new {
+			ov, ok := old[name]
+			if ok && ov == v {
+				continue
+			}
+
+			var err error
+			if ok {
+				err = client.putParameterValue(ownerPath, prefix+name, v.(string))
+			} else {
+				err = client.putParameter(ownerPath, &parameter{Name: prefix + name, Value: v.(string)})
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return updatePasswordParameters(d, client, ownerPath)
+}
+
+// passwordParameterRawValue is the spec of the parameters declared in password_params
+const passwordParameterRawValue = "password"
+
+// updatePasswordParameters applies the changes of the password_params map of a project or build configuration.
+// Since TeamCity never returns their values, all of them are written again when password_rotation_trigger changes.
+func updatePasswordParameters(d *schema.ResourceData, client *Client, ownerPath string) error {
+	rotate := d.HasChange("password_rotation_trigger")
+	if !d.HasChange("password_params") && !rotate {
+		return nil
+	}
+	o, n := d.GetChange("password_params")
+	old, new := o.(map[string]interface{}), n.(map[string]interface{})
+
+	for name := range old {
+		if _, ok := new[name]; ok {
+			continue
+		}
+		if err := client.deleteParameter(ownerPath, name); err != nil && !isNotFound(err) {
+			return err
+		}
+	}
+	for name, v := range new {
+		if ov, ok := old[name]; ok && ov == v && !rotate {
+			continue
+		}
+		// The whole parameter is written rather than its value only, so that the value is redacted from logs
+		dt := &parameter{Name: name, Value: v.(string), Type: &parameterType{RawValue: passwordParameterRawValue}}
+		if err := client.putParameter(ownerPath, dt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flattenParameterCollection sets the parameter maps of a project or build configuration.
+// Inherited parameters and parameters with a spec, which are managed by teamcity_project_parameter and teamcity_build_config_parameter, are skipped.
+func flattenParameterCollection(d *schema.ResourceData, params []*parameter) error {
+	maps := map[string]map[string]string{}
+	for key := range parameterPrefixes {
+		maps[key] = make(map[string]string)
+	}
+
+	for _, p := range params {
+		if p.Inherited || p.Type != nil {
+			continue
+		}
+		key, name := parameterMapKey(p.Name)
+		maps[key][name] = p.Value
+	}
+
+	for key, m := range maps {
+		if len(m) == 0 {
+			continue
+		}
+		if err := d.Set(key, m); err != nil {
+			return err
+		}
+	}
+	return flattenPasswordParameters(d, params)
+}
+
+// flattenPasswordParameters removes from password_params the parameters deleted in TeamCity, or no longer password-typed.
+// Their values are write-only: TeamCity returns them scrambled or empty, so the values in state are kept.
+func flattenPasswordParameters(d *schema.ResourceData, params []*parameter) error {
+	current := d.Get("password_params").(map[string]interface{})
+	if len(current) == 0 {
+		return nil
+	}
+
+	out := make(map[string]interface{})
+	for _, p := range params {
+		if p.Inherited || p.Type == nil || !strings.HasPrefix(p.Type.RawValue, passwordParameterRawValue) {
+			continue
+		}
+		if v, ok := current[p.Name]; ok {
+			out[p.Name] = v
+		}
+	}
+	return d.Set("password_params", out)
+}
+
+// parameterMapKey returns the parameter map holding a parameter, and its name without prefix
+func parameterMapKey(name string) (string, string) {
+	for key, prefix := range parameterPrefixes {
+		if prefix != "" && strings.HasPrefix(name, prefix) {
+			return key, strings.TrimPrefix(name, prefix)
+		}
+	}
+	return "config_params", name
+}
+
+// parameterSpec is the spec of a typed parameter, stored by TeamCity as a raw value in the form of
+// <type> key1='value1' key2='value2', where values are escaped with '|'
+type parameterSpec struct {
+	Type string
+	Args map[string]string
+}
+
+var parameterSpecEscaper = strings.NewReplacer("|", "||", "'", "|'", "\n", "|n", "\r", "|r", "[", "|[", "]", "|]")
+
+func (s *parameterSpec) String() string {
+	keys := make([]string, 0, len(s.Args))
+	for k := range s.Args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := s.Type
+	for _, k := range keys {
+		out += fmt.Sprintf(" %s='%s'", k, parameterSpecEscaper.Replace(s.Args[k]))
+	}
+	return out
+}
+
+func parseParameterSpec(raw string) (*parameterSpec, error) {
+	raw = strings.TrimSpace(raw)
+	out := &parameterSpec{Args: map[string]string{}}
+
+	i := strings.IndexAny(raw, " \t\n")
+	if i < 0 {
+		out.Type = raw
+		return out, nil
+	}
+	out.Type, raw = raw[:i], raw[i:]
+
+	for {
+		raw = strings.TrimLeft(raw, " \t\n")
+		if raw == "" {
+			return out, nil
+		}
+
+		eq := strings.Index(raw, "='")
+		if eq <= 0 {
+			return nil, fmt.Errorf("invalid parameter spec near '%s'", raw)
+		}
+		key := raw[:eq]
+		raw = raw[eq+2:]
+
+		var value strings.Builder
+		closed := false
+		for j := 0; j < len(raw); j++ {
+			c := raw[j]
+			if c == '|' && j+1 < len(raw) {
+				j++
+				switch raw[j] {
+				case 'n':
+					value.WriteByte('\n')
+				case 'r':
+					value.WriteByte('\r')
+				default:
+					value.WriteByte(raw[j])
+				}
+				continue
+			}
+			if c == '\'' {
+				raw = raw[j+1:]
+				closed = true
+				break
+			}
+			value.WriteByte(c)
+		}
+		if !closed {
+			return nil, fmt.Errorf("unterminated value of '%s' in parameter spec", key)
+		}
+		out.Args[key] = value.String()
+	}
+}
+
+// parameterKinds maps the kinds of standalone parameters to the prefix of their names
+var parameterKinds = map[string]string{
+	"configuration": "",
+	"system":        "system.",
+	"environment":   "env.",
+}
+
+// parameterSchema returns the arguments shared by teamcity_project_parameter and teamcity_build_config_parameter
+func parameterSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.NoZeroValues,
+			Description:  "Name of the parameter, without the 'system.' or 'env.' prefix",
+		},
+		"kind": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			Default:      "configuration",
+			ValidateFunc: validation.StringInSlice([]string{"configuration", "system", "environment"}, false),
+			Description:  "Kind of the parameter. Allowed values: 'configuration', 'system', 'environment'",
+		},
+		"value": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: "Value of the parameter. Use password_value for 'password' parameters",
+		},
+		"password_value": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "Value of a 'password' parameter. It is write-only: TeamCity never returns it, so changes made outside Terraform are not detected",
+		},
+		"rotation_trigger": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Arbitrary value: changing it writes password_value again, e.g. after the password was changed outside Terraform",
+		},
+		"type": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "text",
+			ValidateFunc: validation.StringInSlice([]string{"text", "password", "checkbox", "select"}, false),
+			Description:  "Type of the parameter, defining the control used to edit it when running a custom build. Allowed values: 'text', 'password', 'checkbox', 'select'",
+		},
+		"label": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"description": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"display": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "normal",
+			ValidateFunc: validation.StringInSlice([]string{"normal", "hidden", "prompt"}, false),
+			Description:  "Display mode of the parameter when running a custom build. Allowed values: 'normal', 'hidden', 'prompt'",
+		},
+		"read_only": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"validation_regex": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringIsValidRegExp,
+			Description:  "Regular expression the value of a 'text' parameter must match",
+		},
+		"validation_message": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Message shown when the value does not match validation_regex",
+		},
+		"checked_value": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Value of a 'checkbox' parameter when checked",
+		},
+		"unchecked_value": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Value of a 'checkbox' parameter when unchecked",
+		},
+		"option": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Options of a 'select' parameter",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"value": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"label": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+				},
+			},
+		},
+		"allow_multiple": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "If true, several options of a 'select' parameter can be selected",
+		},
+		"value_separator": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Separator of the options selected in a 'select' parameter allowing multiple options",
+		},
+	}
+}
+
+// parameterTypeFields lists the arguments only supported by some parameter types
+var parameterTypeFields = map[string][]string{
+	"password_value":     {"password"},
+	"validation_regex":   {"text"},
+	"validation_message": {"text"},
+	"checked_value":      {"checkbox"},
+	"unchecked_value":    {"checkbox"},
+	"option":             {"select"},
+	"allow_multiple":     {"select"},
+	"value_separator":    {"select"},
+}
+
+// validateParameterSpec checks the arguments set are supported by the parameter type
+func validateParameterSpec(diff *schema.ResourceDiff, meta interface{}) error {
+	if !diff.NewValueKnown("type") {
+		return nil
+	}
+	t := diff.Get("type").(string)
+	if v, ok := diff.GetOk("value"); ok && t == "password" && v.(string) != "" {
+		return fmt.Errorf("'value' cannot be set with parameter type 'password', use 'password_value'")
+	}
+
+	for field, types := range parameterTypeFields {
+		if _, ok := diff.GetOk(field); !ok || !diff.NewValueKnown(field) {
+			continue
+		}
+		supported := false
+		for _, v := range types {
+			supported = supported || v == t
+		}
+		if !supported {
+			return fmt.Errorf("'%s' cannot be set with parameter type '%s'", field, t)
+		}
+	}
+	if t == "select" {
+		if v, ok := diff.GetOk("option"); (!ok || len(v.([]interface{})) == 0) && diff.NewValueKnown("option") {
+			return fmt.Errorf("parameter type 'select' requires at least one 'option'")
+		}
+	}
+	return nil
+}
+
+func expandParameter(d *schema.ResourceData) *parameter {
+	spec := &parameterSpec{Type: d.Get("type").(string), Args: map[string]string{}}
+	setArg := func(key string, value string) {
+		if value != "" {
+			spec.Args[key] = value
+		}
+	}
+
+	setArg("label", d.Get("label").(string))
+	setArg("description", d.Get("description").(string))
+	setArg("display", d.Get("display").(string))
+	if d.Get("read_only").(bool) {
+		spec.Args["readOnly"] = "true"
+	}
+
+	switch spec.Type {
+	case "text":
+		if v := d.Get("validation_regex").(string); v != "" {
+			spec.Args["validationMode"] = "regex"
+			spec.Args["regexp"] = v
+			setArg("validationMessage", d.Get("validation_message").(string))
+		} else {
+			spec.Args["validationMode"] = "any"
+		}
+	case "checkbox":
+		setArg("checkedValue", d.Get("checked_value").(string))
+		setArg("uncheckedValue", d.Get("unchecked_value").(string))
+	case "select":
+		for i, raw := range d.Get("option").([]interface{}) {
+			option := raw.(map[string]interface{})
+			spec.Args[fmt.Sprintf("data_%d", i+1)] = option["value"].(string)
+			setArg(fmt.Sprintf("label_%d", i+1), option["label"].(string))
+		}
+		if d.Get("allow_multiple").(bool) {
+			spec.Args["multiple"] = "true"
+			setArg("valueSeparator", d.Get("value_separator").(string))
+		}
+	}
+
+	value := d.Get("value").(string)
+	if spec.Type == "password" {
+		value = d.Get("password_value").(string)
+	}
+
+	return &parameter{
+		Name:  parameterKinds[d.Get("kind").(string)] + d.Get("name").(string),
+		Value: value,
+		Type:  &parameterType{RawValue: spec.String()},
+	}
+}
+
+func flattenParameter(d *schema.ResourceData, dt *parameter) error {
+	kind, name := "configuration", dt.Name
+	for k, prefix := range parameterKinds {
+		if prefix != "" && strings.HasPrefix(dt.Name, prefix) {
+			kind, name = k, strings.TrimPrefix(dt.Name, prefix)
+		}
+	}
+
+	spec := &parameterSpec{Type: "text", Args: map[string]string{}}
+	if dt.Type != nil && dt.Type.RawValue != "" {
+		var err error
+		if spec, err = parseParameterSpec(dt.Type.RawValue); err != nil {
+			return err
+		}
+	}
+	args := spec.Args
+
+	display := args["display"]
+	if display == "" {
+		display = "normal"
+	}
+
+	var options []map[string]interface{}
+	for i := 1; ; i++ {
+		v, ok := args[fmt.Sprintf("data_%d", i)]
+		if !ok {
+			break
+		}
+		options = append(options, map[string]interface{}{"value": v, "label": args[fmt.Sprintf("label_%d", i)]})
+	}
+
+	values := map[string]interface{}{
+		"name":               name,
+		"kind":               kind,
+		"type":               spec.Type,
+		"label":              args["label"],
+		"description":        args["description"],
+		"display":            display,
+		"read_only":          args["readOnly"] == "true",
+		"validation_regex":   args["regexp"],
+		"validation_message": args["validationMessage"],
+		"checked_value":      args["checkedValue"],
+		"unchecked_value":    args["uncheckedValue"],
+		"option":             options,
+		"allow_multiple":     args["multiple"] == "true",
+		"value_separator":    args["valueSeparator"],
+		"value":              dt.Value,
+	}
+	// The value of password parameters is write-only: TeamCity returns it scrambled or empty, so password_value is kept from state
+	if spec.Type == "password" {
+		values["value"] = ""
+	}
+
+	for k, v := range values {
+		if err := d.Set(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parameterID returns the ID of a standalone parameter, in the form of <owner_id>/<name>, where name has the 'system.' or 'env.' prefix
+func parameterID(ownerID string, name string) string {
+	return fmt.Sprintf("%s/%s", ownerID, name)
+}
+
+func parseParameterID(id string) (ownerID string, name string, err error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid parameter ID '%s', expected '<owner_id>/<name>'", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+// parameterOwner describes where the parameters of a standalone parameter resource are defined
+type parameterOwner struct {
+	attribute string // the owner ID attribute, e.g. project_id
+	kind      string // the owner kind used in messages, e.g. project
+	path      func(id string) string
+}
+
+// resourceParameter returns a standalone parameter resource, defined in owner and identified by the owner ID attribute
+func resourceParameter(owner parameterOwner, ownerSchema *schema.Schema) *schema.Resource {
+	s := parameterSchema()
+	s[owner.attribute] = ownerSchema
+
+	return &schema.Resource{
+		Create: createContext(owner.create),
+		Read:   readContext(owner.read),
+		Update: updateContext(owner.update),
+		Delete: deleteContext(owner.delete),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+		CustomizeDiff: validateParameterSpec,
+
+		Schema: s,
+	}
+}
+
+func (o parameterOwner) create(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	ownerID := d.Get(o.attribute).(string)
+	dt := expandParameter(d)
+
+	// PUT overwrites the parameter, which must not silently take over one defined in the UI or by the parameter maps.
+	// Parameters only inherited from a parent project or template can be overridden.
+	existing, err := client.getParameter(o.path(ownerID), dt.Name)
+	if err == nil && !existing.Inherited {
+		return fmt.Errorf("parameter '%s' already exists in %s '%s', import it instead", dt.Name, o.kind, ownerID)
+	}
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+
+	log.Printf("[INFO] Creating parameter '%s' in %s '%s'", dt.Name, o.kind, ownerID)
+	if err := client.putParameter(o.path(ownerID), dt); err != nil {
+		return err
+	}
+	d.SetId(parameterID(ownerID, dt.Name))
+
+	return o.read(ctx, d, meta)
+}
+
+func (o parameterOwner) update(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	log.Printf("[INFO] Updating parameter '%s'", d.Id())
+	if err := client.putParameter(o.path(d.Get(o.attribute).(string)), expandParameter(d)); err != nil {
+		return err
+	}
+
+	return o.read(ctx, d, meta)
+}
+
+func (o parameterOwner) read(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	ownerID, name, err := parseParameterID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	dt, err := client.getParameter(o.path(ownerID), name)
+	if err != nil {
+		return handleNotFound(d, client, parameterPath(o.path(ownerID), name), err)
+	}
+	if dt.Inherited {
+		log.Printf("[WARN] parameter '%s' is only inherited by %s '%s', removing from state", name, o.kind, ownerID)
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set(o.attribute, ownerID); err != nil {
+		return err
+	}
+	return flattenParameter(d, dt)
+}
+
+func (o parameterOwner) delete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	ownerID, name, err := parseParameterID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	err = meta.(*Client).deleteParameter(o.path(ownerID), name)
+	if isNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -1,18 +1,44 @@
 package teamcity_test
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
 	"github.com/leidruid/terraform-provider-teamcity/teamcity"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	api "github.com/leidruid/go-teamcity/teamcity"
 )
 
 var testAccProviders map[string]terraform.ResourceProvider
 var testAccProvider *schema.Provider
 
+// testAccFakeServer is the fake TeamCity server acceptance tests run against, or nil when they run against a real server
+var testAccFakeServer *fakeTeamCity
+
+// TestMain starts the fake TeamCity server when TEAMCITY_FAKE_SERVER is set, and runs acceptance tests against it
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeServerEnvVar) == "" {
+		os.Exit(m.Run())
+	}
+
+	testAccFakeServer = newFakeTeamCity()
+	srv := httptest.NewServer(testAccFakeServer)
+	os.Setenv(resource.TestEnvVar, "1")
+	os.Setenv("TEAMCITY_ADDR", srv.URL)
+	os.Setenv("TEAMCITY_TOKEN", "fake-token")
+	os.Unsetenv("TEAMCITY_USER")
+	os.Unsetenv("TEAMCITY_PASSWORD")
+
+	code := m.Run()
+	srv.Close()
+	os.Exit(code)
+}
+
 func init() {
 	testAccProvider = teamcity.Provider().(*schema.Provider)
 	testAccProviders = map[string]terraform.ResourceProvider{
@@ -20,6 +46,11 @@ func init() {
 	}
 }
 
+// testAccClient returns the go-teamcity client configured by the provider under test
+func testAccClient() *api.Client {
+	return testAccProvider.Meta().(*teamcity.Client).Client
+}
+
 func TestProvider(t *testing.T) {
 	if err := teamcity.Provider().(*schema.Provider).InternalValidate(); err != nil {
 		t.Fatalf("Error: %s", err)
@@ -34,10 +65,38 @@ func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("TEAMCITY_ADDR"); v == "" {
 		t.Fatal("TEAMCITY_ADDR must be set for acceptance tests")
 	}
+	if v := os.Getenv("TEAMCITY_TOKEN"); v != "" {
+		return
+	}
 	if v := os.Getenv("TEAMCITY_USER"); v == "" {
-		t.Fatal("TEAMCITY_USER must be set for acceptance tests")
+		t.Fatal("TEAMCITY_USER or TEAMCITY_TOKEN must be set for acceptance tests")
 	}
 	if v := os.Getenv("TEAMCITY_PASSWORD"); v == "" {
 		t.Fatal("TEAMCITY_PASSWORD must be set for acceptance tests")
 	}
 }
+
+// testProviderWithHandler configures a provider against a local server answering REST calls with h.
+// The server information queried at configure time is answered separately.
+func testProviderWithHandler(t *testing.T, h http.Handler) *schema.Provider {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/app/rest/server" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"version":"2020.1.3 (build 78938)","buildNumber":"78938"}`))
+			return
+		}
+		h.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	p := teamcity.Provider().(*schema.Provider)
+	err := p.Configure(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"address":      srv.URL,
+		"access_token": "token",
+		"max_retries":  0,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return p
+}
@@ -1,6 +1,7 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
@@ -11,12 +12,16 @@ import (
 
 func resourceAgentRequirement() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceAgentRequirementCreate,
-		Read:   resourceAgentRequirementRead,
-		Delete: resourceAgentRequirementDelete,
+		Create: createContext(resourceAgentRequirementCreate),
+		Read:   readContext(resourceAgentRequirementRead),
+		Delete: deleteContext(resourceAgentRequirementDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"build_config_id": {
@@ -44,20 +49,18 @@ func resourceAgentRequirement() *schema.Resource {
 	}
 }
 
-func resourceAgentRequirementCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceAgentRequirementCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	var buildConfigID string
 
 	if v, ok := d.GetOk("build_config_id"); ok {
 		buildConfigID = v.(string)
 	}
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
+	if _, err := getBuildConfiguration(client, buildConfigID); err != nil {
 		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
 	}
 
-	srv := client.AgentRequirementService(buildConfigID)
-
 	var condition, name, value string
 
 	if v, ok := d.GetOk("condition"); ok {
@@ -76,7 +79,7 @@ func resourceAgentRequirementCreate(d *schema.ResourceData, meta interface{}) er
 	if err != nil {
 		return err
 	}
-	out, err := srv.Create(dt)
+	out, err := addAgentRequirement(client, buildConfigID, dt)
 
 	if err != nil {
 		return err
@@ -84,15 +87,13 @@ func resourceAgentRequirementCreate(d *schema.ResourceData, meta interface{}) er
 
 	d.SetId(out.ID)
 
-	return resourceAgentRequirementRead(d, meta)
+	return resourceAgentRequirementRead(ctx, d, meta)
 }
 
-func resourceAgentRequirementRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).AgentRequirementService(d.Get("build_config_id").(string))
-
-	dt, err := getAgentRequirement(client, d.Id())
+func resourceAgentRequirementRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	dt, err := getAgentRequirement(meta.(*Client), d.Get("build_config_id").(string), d.Id())
 	if err != nil {
-		return err
+		return handleNotFound(d, meta.(*Client), buildTypeItemPath(d.Get("build_config_id").(string), "agent-requirements", d.Id()), err)
 	}
 
 	if err := d.Set("build_config_id", dt.BuildTypeID); err != nil {
@@ -116,18 +117,28 @@ func resourceAgentRequirementRead(d *schema.ResourceData, meta interface{}) erro
 	return nil
 }
 
-func resourceAgentRequirementDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceAgentRequirementDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	svr := client.AgentRequirementService(d.Get("build_config_id").(string))
 
 	return svr.Delete(d.Id())
 }
 
-func getAgentRequirement(c *api.AgentRequirementService, id string) (*api.AgentRequirement, error) {
-	dt, err := c.GetByID(id)
-	if err != nil {
+// addAgentRequirement and getAgentRequirement replace their go-teamcity counterparts, which are sent with http.DefaultClient and would skip the provider transport settings
+func addAgentRequirement(c *Client, buildTypeID string, dt *api.AgentRequirement) (*api.AgentRequirement, error) {
+	var out api.AgentRequirement
+	if err := c.doRequest("POST", buildTypePath(buildTypeID)+"/agent-requirements", dt, &out, "agent requirement"); err != nil {
 		return nil, err
 	}
+	out.BuildTypeID = buildTypeID
+	return &out, nil
+}
 
-	return dt, nil
+func getAgentRequirement(c *Client, buildTypeID string, id string) (*api.AgentRequirement, error) {
+	var out api.AgentRequirement
+	if err := c.doRequest("GET", buildTypeItemPath(buildTypeID, "agent-requirements", id), nil, &out, "agent requirement"); err != nil {
+		return nil, err
+	}
+	out.BuildTypeID = buildTypeID
+	return &out, nil
 }
@@ -73,7 +73,7 @@ func TestAccTeamcityAgentRequirement_Update(t *testing.T) {
 
 func testAccCheckTeamcityAgentRequirementDestroy(bt *string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccClient()
 		return AgentRequirementDestroyHelper(s, bt, client)
 	}
 }
@@ -101,7 +101,7 @@ func AgentRequirementDestroyHelper(s *terraform.State, bt *string, client *api.C
 
 func testAccCheckTeamcityAgentRequirementExists(n string, bt *string, snap *api.AgentRequirement) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccClient()
 		return teamcityAgentRequirementExistsHelper(n, bt, s, client, snap)
 	}
 }
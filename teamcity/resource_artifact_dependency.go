@@ -1,6 +1,7 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -10,12 +11,16 @@ import (
 
 func resourceArtifactDependency() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceArtifactDependencyCreate,
-		Read:   resourceArtifactDependencyRead,
-		Delete: resourceArtifactDependencyDelete,
+		Create: createContext(resourceArtifactDependencyCreate),
+		Read:   readContext(resourceArtifactDependencyRead),
+		Delete: deleteContext(resourceArtifactDependencyDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"build_config_id": {
@@ -63,19 +68,18 @@ func resourceArtifactDependency() *schema.Resource {
 	}
 }
 
-func resourceArtifactDependencyCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceArtifactDependencyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	var buildConfigID string
 
 	if v, ok := d.GetOk("build_config_id"); ok {
 		buildConfigID = v.(string)
 	}
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
+	if _, err := getBuildConfiguration(client, buildConfigID); err != nil {
 		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
 	}
 
-	depService := client.DependencyService(buildConfigID)
 	opt, err := expandArtifactDependencyOptions(d)
 	if err != nil {
 		return err
@@ -85,7 +89,7 @@ func resourceArtifactDependencyCreate(d *schema.ResourceData, meta interface{})
 		return err
 	}
 
-	out, err := depService.AddArtifactDependency(dep)
+	out, err := addArtifactDependency(client, buildConfigID, dep)
 
 	if err != nil {
 		return err
@@ -93,15 +97,15 @@ func resourceArtifactDependencyCreate(d *schema.ResourceData, meta interface{})
 
 	d.SetId(out.ID())
 
-	return resourceArtifactDependencyRead(d, meta)
+	return resourceArtifactDependencyRead(ctx, d, meta)
 }
 
-func resourceArtifactDependencyRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).DependencyService(d.Get("build_config_id").(string))
+func resourceArtifactDependencyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).DependencyService(d.Get("build_config_id").(string))
 
 	dt, err := getArtifactDependency(client, d.Id())
 	if err != nil {
-		return err
+		return handleNotFound(d, meta.(*Client), buildTypeItemPath(d.Get("build_config_id").(string), "artifact-dependencies", d.Id()), err)
 	}
 
 	if err := d.Set("build_config_id", dt.BuildTypeID()); err != nil {
@@ -129,13 +133,23 @@ func resourceArtifactDependencyRead(d *schema.ResourceData, meta interface{}) er
 	return d.Set("source_build_config_id", dt.SourceBuildTypeID)
 }
 
-func resourceArtifactDependencyDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceArtifactDependencyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	dep := client.DependencyService(d.Get("build_config_id").(string))
 
 	return dep.DeleteArtifact(d.Id())
 }
 
+// addArtifactDependency replaces its go-teamcity counterpart, which is sent with http.DefaultClient and would skip the provider transport settings
+func addArtifactDependency(c *Client, buildTypeID string, dep *api.ArtifactDependency) (*api.ArtifactDependency, error) {
+	var out api.ArtifactDependency
+	if err := c.doRequest("POST", buildTypePath(buildTypeID)+"/artifact-dependencies", dep, &out, "artifact dependency"); err != nil {
+		return nil, err
+	}
+	out.SetBuildTypeID(buildTypeID)
+	return &out, nil
+}
+
 func getArtifactDependency(c *api.DependencyService, id string) (*api.ArtifactDependency, error) {
 
 	dt, err := c.GetArtifactByID(id)
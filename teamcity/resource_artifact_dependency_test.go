@@ -202,7 +202,7 @@ func testAccCheckDependencySourceBuildType(n string, dep *api.ArtifactDependency
 
 func testAccCheckTeamcityArtifactDependencyDestroy(bt *string, resourceType string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccClient()
 		return ArtifactDependencyDestroyHelper(s, bt, client, resourceType)
 	}
 }
@@ -230,7 +230,7 @@ func ArtifactDependencyDestroyHelper(s *terraform.State, bt *string, client *api
 
 func testAccCheckTeamcityArtifactDependencyExists(n string, bt *string, snap *api.ArtifactDependency) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccClient()
 		return teamcityArtifactDependencyExistsHelper(n, bt, s, client, snap)
 	}
 }
@@ -2,6 +2,8 @@ package teamcity
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"reflect"
@@ -14,13 +16,18 @@ import (
 )
 
 func resourceBuildConfig() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceBuildConfigCreate,
-		Read:   resourceBuildConfigRead,
-		Update: resourceBuildConfigUpdate,
-		Delete: resourceBuildConfigDelete,
+	r := &schema.Resource{
+		Create: createContext(resourceBuildConfigCreate),
+		Read:   readContext(resourceBuildConfigRead),
+		Update: updateContext(resourceBuildConfigUpdate),
+		Delete: deleteContext(resourceBuildConfigDelete),
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceBuildConfigImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
 		},
 
 		CustomizeDiff: func(diff *schema.ResourceDiff, v interface{}) error {
@@ -115,7 +122,7 @@ func resourceBuildConfig() *schema.Resource {
 						"type": {
 							Type:         schema.TypeString,
 							Required:     true,
-							ValidateFunc: validation.StringInSlice([]string{"powershell", "cmd_line", "gradle", "docker"}, false),
+							ValidateFunc: validation.StringInSlice([]string{"powershell", "cmd_line", "gradle", "docker", "maven", "dotnet", "python", "nodejs", "kotlin_script", "ssh_exec", "ssh_upload", "generic"}, false),
 						},
 						"name": {
 							Type:     schema.TypeString,
@@ -127,6 +134,12 @@ func resourceBuildConfig() *schema.Resource {
 							Optional:     true,
 							ValidateFunc: validation.StringInSlice([]string{"default", "execute_if_success", "execute_if_failed", "execute_always"}, false),
 						},
+						"enabled": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: "If false, the step is disabled and skipped by builds",
+						},
 						"execute_conditions": {
 							Type:     schema.TypeList,
 							Optional: true,
@@ -192,6 +205,162 @@ func resourceBuildConfig() *schema.Resource {
 							Type:     schema.TypeString,
 							Optional: true,
 						},
+						"goals": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Goals of a 'maven' step",
+						},
+						"maven_version": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Version of the Maven installation used by a 'maven' step, as provided by TeamCity tools. Uses the default installation if empty",
+						},
+						"jdk_home": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"jvm_args": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"command": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"build", "test", "publish", "pack", "nuget-push"}, false),
+							Description:  "Command of a 'dotnet' step",
+						},
+						"projects": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Projects, solutions or packages a 'dotnet' step applies to. Wildcards are supported",
+						},
+						"configuration": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"framework": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"runtime": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"output_dir": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"version_suffix": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"skip_build": {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						"test_filter": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"nuget_source": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"nuget_api_key": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "API key of the NuGet feed of a 'dotnet' step running 'nuget-push'. It is write-only: TeamCity never returns it",
+						},
+						"module": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Module run by a 'python' step",
+						},
+						"python_executable": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"commands": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Commands run by a 'nodejs' step, e.g. 'npm ci', or on the remote host by an 'ssh_exec' step",
+						},
+						"kotlin_version": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Version of the Kotlin compiler used by a 'kotlin_script' step, as provided by TeamCity tools. Uses the default compiler if empty",
+						},
+						"host": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Target host of an 'ssh_exec' or 'ssh_upload' step",
+						},
+						"port": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntBetween(1, 65535),
+						},
+						"username": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"auth_method": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"uploaded_key", "password"}, false),
+							Description:  "Authentication method of an 'ssh_exec' or 'ssh_upload' step",
+						},
+						"ssh_key": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Name of the SSH key uploaded to the project used with the 'uploaded_key' auth method",
+						},
+						"password": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Password of the 'password' auth method, or passphrase of the uploaded key. It is write-only: TeamCity never returns it",
+						},
+						"timeout": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "Timeout of an 'ssh_exec' or 'ssh_upload' step, in seconds",
+						},
+						"upload_rules": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Artifact path rules of the files uploaded by an 'ssh_upload' step",
+						},
+						"target_dir": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"transport": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"scp", "sftp"}, false),
+							Description:  "Transport of an 'ssh_upload' step, 'scp' if empty",
+							// Unset means scp, which TeamCity returns explicitly
+							DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+								return old == "scp" && new == ""
+							},
+						},
+						"runner_type": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Runner type of a 'generic' step, e.g. 'Maven2', the type of a plugin runner or the ID of a meta-runner",
+						},
+						"properties": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Properties of a 'generic' step, passed verbatim to TeamCity",
+						},
 					},
 				},
 			},
@@ -207,6 +376,17 @@ func resourceBuildConfig() *schema.Resource {
 				Type:     schema.TypeMap,
 				Optional: true,
 			},
+			"password_params": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "A map of password parameters, by full name including the 'system.' or 'env.' prefix. Values are write-only: TeamCity never returns them",
+			},
+			"password_rotation_trigger": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Arbitrary value: changing it writes all password_params again, e.g. after they were changed outside Terraform",
+			},
 			"settings": {
 				Type:       schema.TypeSet,
 				Optional:   true,
@@ -267,7 +447,7 @@ func resourceBuildConfig() *schema.Resource {
 				Optional: true,
 			},
 		},
-		SchemaVersion: 1,
+		SchemaVersion: 2,
 		StateUpgraders: []schema.StateUpgrader{
 			{
 				Type:    resourceBuildConfigInstanceResourceV0().CoreConfigSchema().ImpliedType(),
@@ -276,6 +456,14 @@ func resourceBuildConfig() *schema.Resource {
 			},
 		},
 	}
+
+	// Version 1 only lacks step.enabled, which the current schema decodes as null
+	r.StateUpgraders = append(r.StateUpgraders, schema.StateUpgrader{
+		Type:    r.CoreConfigSchema().ImpliedType(),
+		Upgrade: resourceBuildConfigInstanceStateUpgradeV1,
+		Version: 1,
+	})
+	return r
 }
 
 func buildCounterChange(o *api.BuildTypeOptions, n *api.BuildTypeOptions) bool {
@@ -289,8 +477,8 @@ func buildCounterChange(o *api.BuildTypeOptions, n *api.BuildTypeOptions) bool {
 		o.BuildCounter != n.BuildCounter
 }
 
-func resourceBuildConfigCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceBuildConfigCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	var projectID, name string
 	isTemplate := false
 
@@ -357,11 +545,11 @@ func resourceBuildConfigCreate(d *schema.ResourceData, meta interface{}) error {
 
 	log.Printf("[DEBUG] resourceBuildConfigCreate: initial creation finished. Calling resourceBuildConfigUpdate to update the rest of resource.")
 
-	return resourceBuildConfigUpdate(d, meta)
+	return resourceBuildConfigUpdate(ctx, d, meta)
 }
 
-func resourceBuildConfigUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceBuildConfigUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	dt, err := getBuildConfiguration(client, d.Id())
 	log.Printf("[DEBUG] resourceBuildConfigUpdate started for resouceId: %v", d.Id())
 
@@ -370,14 +558,6 @@ func resourceBuildConfigUpdate(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	var changed bool
-	if d.HasChange("sys_params") || d.HasChange("config_params") || d.HasChange("env_params") {
-		log.Printf("[DEBUG] resourceBuildConfigUpdate: change detected for params")
-		dt.Parameters, err = expandParameterCollection(d)
-		if err != nil {
-			return err
-		}
-		changed = true
-	}
 	if v, ok := d.GetOk("description"); ok {
 		if d.HasChange("description") {
 			log.Printf("[DEBUG] resourceBuildConfigUpdate: change detected for description")
@@ -394,23 +574,36 @@ func resourceBuildConfigUpdate(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	if changed {
-		_, err := client.BuildTypes.Update(dt)
+		err := client.updateBuildTypeSettings(dt)
 		d.SetPartial("settings")
 		d.SetPartial("description")
-		d.SetPartial("config_params")
-		d.SetPartial("sys_params")
-		d.SetPartial("env_params")
 		if err != nil {
 			return err
 		}
 	}
 
+	if d.HasChange("sys_params") || d.HasChange("config_params") || d.HasChange("env_params") ||
+		d.HasChange("password_params") || d.HasChange("password_rotation_trigger") {
+		log.Printf("[DEBUG] resourceBuildConfigUpdate: change detected for params")
+		if err := updateParameterCollection(d, client, buildTypePath(dt.ID)); err != nil {
+			return err
+		}
+		d.SetPartial("config_params")
+		d.SetPartial("sys_params")
+		d.SetPartial("env_params")
+		d.SetPartial("password_params")
+		d.SetPartial("password_rotation_trigger")
+	}
+
 	if v, ok := d.GetOk("vcs_root"); ok {
 		vcs := v.(*schema.Set).List()
 		for _, raw := range vcs {
 			toAttach := buildVcsRootEntry(raw)
+			if vcsRootEntryAttached(dt, toAttach.VcsRoot.ID) {
+				continue
+			}
 
-			err := client.BuildTypes.AttachVcsRootEntry(dt.ID, toAttach)
+			err := attachVcsRootEntry(client, dt.ID, toAttach)
 			if err != nil {
 				return err
 			}
@@ -422,32 +615,15 @@ func resourceBuildConfigUpdate(d *schema.ResourceData, meta interface{}) error {
 	if d.HasChange("step") {
 		log.Printf("[DEBUG] resourceBuildConfigUpdate: change detected for steps")
 		o, n := d.GetChange("step")
-		os := o.([]interface{})
-		ns := n.([]interface{})
-
-		remove, _ := expandBuildSteps(os)
-		add, err := expandBuildSteps(ns)
-
-		if err != nil {
+		steps, err := updateBuildSteps(client, dt.ID, o.([]interface{}), n.([]interface{}))
+		// The step IDs in state tell the steps of the step block from the other steps of the build configuration
+		if err := d.Set("step", buildStepsState(o.([]interface{}), n.([]interface{}), steps, err != nil)); err != nil {
 			return err
 		}
-		if len(remove) > 0 {
-			for _, s := range remove {
-				err := client.BuildTypes.DeleteStep(dt.ID, s.GetID())
-				if err != nil {
-					return err
-				}
-			}
-		}
-		if len(add) > 0 {
-			for _, s := range add {
-				_, err := client.BuildTypes.AddStep(dt.ID, s)
-				if err != nil {
-					return err
-				}
-			}
-		}
 		d.SetPartial("step")
+		if err != nil {
+			return err
+		}
 	}
 
 	if d.HasChange("templates") {
@@ -473,22 +649,38 @@ func resourceBuildConfigUpdate(d *schema.ResourceData, meta interface{}) error {
 
 	d.Partial(false)
 	log.Printf("[DEBUG] resourceBuildConfigUpdate: updated finished. Calling 'read' to refresh state.")
-	return resourceBuildConfigRead(d, meta)
+	return resourceBuildConfigRead(ctx, d, meta)
 }
 
-func resourceBuildConfigDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceBuildConfigDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	log.Printf("[DEBUG] resourceBuildConfigDelete: destroying build configuration '%v'.", d.Id())
 	return client.BuildTypes.Delete(d.Id())
 }
 
-func resourceBuildConfigRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+// resourceBuildConfigImport adds all the steps of the build configuration to the step block, which only reads the steps in state
+func resourceBuildConfigImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	steps, err := meta.(*Client).getOwnBuildSteps(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	stepIDs := make([]map[string]interface{}, 0, len(steps))
+	for _, s := range steps {
+		stepIDs = append(stepIDs, map[string]interface{}{"step_id": s.ID})
+	}
+	if err := d.Set("step", stepIDs); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceBuildConfigRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 
 	log.Printf("[DEBUG] resourceBuildConfigRead started for resouceId: %v", d.Id())
 	dt, err := getBuildConfiguration(client, d.Id())
 	if err != nil {
-		return err
+		return handleNotFound(d, client, buildTypePath(d.Id()), err)
 	}
 	log.Printf("[DEBUG] BuildConfiguration '%v' retrieved successfully", dt.Name)
 	if err := d.Set("name", dt.Name); err != nil {
@@ -506,7 +698,11 @@ func resourceBuildConfigRead(d *schema.ResourceData, meta interface{}) error {
 	if err := d.Set("project_id", dt.ProjectID); err != nil {
 		return err
 	}
-	if err := flattenParameterCollection(d, dt.Parameters); err != nil {
+	params, err := client.getParameters(buildTypePath(dt.ID))
+	if err != nil {
+		return err
+	}
+	if err := flattenParameterCollection(d, params); err != nil {
 		return err
 	}
 	if err := flattenBuildConfigOptions(d, dt.Options); err != nil {
@@ -532,23 +728,36 @@ func resourceBuildConfigRead(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
-	steps, err := client.BuildTypes.GetSteps(d.Id())
+	// Only the steps created by the step block are read, the others are managed by teamcity_build_step resources or outside Terraform
+	managed := make(map[string]bool)
+	for _, raw := range d.Get("step").([]interface{}) {
+		if id, _ := raw.(map[string]interface{})["step_id"].(string); id != "" {
+			managed[id] = true
+		}
+	}
+	steps, err := client.getOwnBuildSteps(d.Id())
 	if err != nil {
 		return err
 	}
-	if steps != nil && len(steps) > 0 {
-		var stepsToSave []map[string]interface{}
-		for _, el := range steps {
-			l, err := flattenBuildStep(el)
-			if err != nil {
-				return err
-			}
-			stepsToSave = append(stepsToSave, l)
+	stepsToSave := make([]map[string]interface{}, 0, len(steps))
+	for _, el := range steps {
+		if !managed[el.ID] {
+			continue
 		}
-
-		if err := d.Set("step", stepsToSave); err != nil {
+		i := len(stepsToSave)
+		var l map[string]interface{}
+		if stepConfiguredAsGeneric(d, i, el) {
+			l = flattenBuildStepGeneric(el)
+			l["step_id"] = el.ID
+			l["enabled"] = !el.Disabled
+		} else if l, err = flattenBuildStep(el); err != nil {
 			return err
 		}
+		preserveStepSecureFields(d, i, l)
+		stepsToSave = append(stepsToSave, l)
+	}
+	if err := d.Set("step", stepsToSave); err != nil {
+		return err
 	}
 
 	return nil
@@ -567,13 +776,39 @@ func validateBuildConfig(d *schema.ResourceData) error {
 	return nil
 }
 
-func getBuildConfiguration(c *api.Client, id string) (*api.BuildType, error) {
-	dt, err := c.BuildTypes.GetByID(id)
+// updateBuildTypeSettings updates the description and settings of a build configuration.
+// go-teamcity's BuildTypes.Update also replaces all parameters, dropping their specs and password values, so it is not used.
+func (c *Client) updateBuildTypeSettings(dt *api.BuildType) error {
+	if err := c.doRequest("PUT", buildTypePath(dt.ID)+"/description", dt.Description, nil, "build type description"); err != nil {
+		return err
+	}
+
+	// settings are only serialized by BuildType.MarshalJSON
+	raw, err := json.Marshal(dt)
 	if err != nil {
+		return err
+	}
+	var aux struct {
+		Settings *api.Properties `json:"settings"`
+	}
+	if err := json.Unmarshal(raw, &aux); err != nil {
+		return err
+	}
+	return c.doRequest("PUT", buildTypePath(dt.ID)+"/settings", aux.Settings, nil, "build type settings")
+}
+
+// getBuildConfiguration replaces BuildTypes.GetByID, which is sent with http.DefaultClient and would skip the provider transport settings
+func getBuildConfiguration(c *Client, id string) (*api.BuildType, error) {
+	var out api.BuildType
+	if err := c.doRequest("GET", buildTypePath(id), nil, &out, "build type"); err != nil {
 		return nil, err
 	}
 
-	return dt, nil
+	// Like go-teamcity, inherited parameters are filtered out
+	if out.Parameters != nil {
+		out.Parameters = out.Parameters.NonInherited()
+	}
+	return &out, nil
 }
 
 var stepTypeMap = map[string]string{
@@ -581,6 +816,13 @@ var stepTypeMap = map[string]string{
 	api.StepTypeCommandLine: "cmd_line",
 	api.StepTypeGradle:      "gradle",
 	api.StepTypeDocker:      "docker",
+	stepTypeMaven:           "maven",
+	stepTypeDotnet:          "dotnet",
+	stepTypePython:          "python",
+	stepTypeNodeJS:          "nodejs",
+	stepTypeKotlin:          "kotlin_script",
+	stepTypeSSHExec:         "ssh_exec",
+	stepTypeSSHUpload:       "ssh_upload",
 }
 
 func flattenTemplates(d *schema.ResourceData, templates *api.Templates) error {
@@ -599,27 +841,6 @@ func flattenTemplates(d *schema.ResourceData, templates *api.Templates) error {
 	return nil
 }
 
-func flattenParameterCollection(d *schema.ResourceData, params *api.Parameters) error {
-	var configParams, sysParams, envParams = flattenParameters(params)
-
-	if len(envParams) > 0 {
-		if err := d.Set("env_params", envParams); err != nil {
-			return err
-		}
-	}
-	if len(sysParams) > 0 {
-		if err := d.Set("sys_params", sysParams); err != nil {
-			return err
-		}
-	}
-	if len(configParams) > 0 {
-		if err := d.Set("config_params", configParams); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 func expandParameterCollection(d *schema.ResourceData) (*api.Parameters, error) {
 	var config, system, env *api.Parameters
 	if v, ok := d.GetOk("env_params"); ok {
@@ -660,21 +881,6 @@ func expandParameterCollection(d *schema.ResourceData) (*api.Parameters, error)
 	return out, nil
 }
 
-func flattenParameters(dt *api.Parameters) (config map[string]string, sys map[string]string, env map[string]string) {
-	env, sys, config = make(map[string]string), make(map[string]string), make(map[string]string)
-	for _, p := range dt.Items {
-		switch p.Type {
-		case api.ParameterTypes.Configuration:
-			config[p.Name] = p.Value
-		case api.ParameterTypes.EnvironmentVariable:
-			env[p.Name] = p.Value
-		case api.ParameterTypes.System:
-			sys[p.Name] = p.Value
-		}
-	}
-	return config, sys, env
-}
-
 func expandParameters(raw map[string]interface{}, paramType string) (*api.Parameters, error) {
 	out := api.NewParametersEmpty()
 	for k, v := range raw {
@@ -747,24 +953,85 @@ func flattenBuildConfigOptionsRaw(dt *api.BuildTypeOptions) map[string]interface
 	return m
 }
 
-func flattenBuildStep(s api.Step) (map[string]interface{}, error) {
-	mapType := stepTypeMap[s.Type()]
+func flattenBuildStep(s *buildStep) (map[string]interface{}, error) {
+	mapType := stepTypeMap[s.Type]
 	var out map[string]interface{}
 	var err error
 	switch mapType {
 	case "powershell":
-		out, err = flattenBuildStepPowershell(s.(*api.StepPowershell)), nil
+		var dt api.StepPowershell
+		if err = s.decode(&dt); err == nil {
+			out = flattenBuildStepPowershell(&dt)
+		}
 	case "cmd_line":
-		out, err = flattenBuildStepCmdLine(s.(*api.StepCommandLine)), nil
+		var dt api.StepCommandLine
+		if err = s.decode(&dt); err == nil {
+			out = flattenBuildStepCmdLine(&dt)
+		}
 	case "gradle":
-		out, err = flattenBuildStepGradle(s.(*api.StepGradle)), nil
+		var dt api.StepGradle
+		if err = s.decode(&dt); err == nil {
+			out = flattenBuildStepGradle(&dt)
+		}
 	case "docker":
-		out, err = flattenBuildStepDocker(s.(*api.StepDocker)), nil
+		var dt api.StepDocker
+		if err = s.decode(&dt); err == nil {
+			out = flattenBuildStepDocker(&dt)
+		}
+	case "maven":
+		out = flattenBuildStepMaven(s)
+	case "dotnet":
+		out = flattenBuildStepDotnet(s)
+	case "python":
+		out = flattenBuildStepPython(s)
+	case "nodejs":
+		out = flattenBuildStepNodeJS(s)
+	case "kotlin_script":
+		out = flattenBuildStepKotlin(s)
+	case "ssh_exec", "ssh_upload":
+		out = flattenBuildStepSSH(s)
 	default:
-		return nil, fmt.Errorf("build step type '%s' not supported", s.Type())
+		out = flattenBuildStepGeneric(s)
+	}
+	if err != nil {
+		return nil, err
+	}
+	out["step_id"] = s.ID
+	out["enabled"] = !s.Disabled
+	return out, nil
+}
+
+// stepConfiguredAsGeneric reports whether the i-th step is declared in state as a generic step of the same runner type.
+// Steps of runner types modelled by the provider are only read as generic steps when declared that way.
+func stepConfiguredAsGeneric(d *schema.ResourceData, i int, s *buildStep) bool {
+	prefix := fmt.Sprintf("step.%d.", i)
+	return d.Get(prefix+"type").(string) == "generic" && d.Get(prefix+"runner_type").(string) == s.Type
+}
+
+// preserveStepSecureFields copies the secure arguments of the i-th step from state, since TeamCity never returns them
+func preserveStepSecureFields(d *schema.ResourceData, i int, m map[string]interface{}) {
+	prefix := fmt.Sprintf("step.%d.", i)
+	if m["type"] != d.Get(prefix+"type").(string) {
+		return
+	}
+
+	switch m["type"] {
+	case "dotnet":
+		for field := range dotnetSecureProperties {
+			m[field] = d.Get(prefix + field)
+		}
+	case "ssh_exec", "ssh_upload":
+		for field := range sshSecureProperties {
+			m[field] = d.Get(prefix + field)
+		}
+	case "generic":
+		props := m["properties"].(map[string]interface{})
+		for name, value := range d.Get(prefix + "properties").(map[string]interface{}) {
+			if strings.HasPrefix(name, "secure:") {
+				props[name] = value
+			}
+		}
 	}
-	out["step_id"] = s.GetID()
-	return out, err
 }
 
 func flattenBuildStepPowershell(s *api.StepPowershell) map[string]interface{} {
@@ -870,8 +1137,8 @@ func flattenBuildStepDocker(s *api.StepDocker) map[string]interface{} {
 	return m
 }
 
-func expandBuildSteps(list interface{}) ([]api.Step, error) {
-	out := make([]api.Step, 0)
+func expandBuildSteps(list interface{}) ([]*buildStep, error) {
+	out := make([]*buildStep, 0)
 	in := list.([]interface{})
 	for _, i := range in {
 		s, err := expandBuildStep(i)
@@ -883,22 +1150,54 @@ func expandBuildSteps(list interface{}) ([]api.Step, error) {
 	return out, nil
 }
 
-func expandBuildStep(raw interface{}) (api.Step, error) {
+func expandBuildStep(raw interface{}) (*buildStep, error) {
 	localStep := raw.(map[string]interface{})
+	s, err := expandBuildStepRunner(localStep)
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := localStep["enabled"]; ok {
+		s.Disabled = !v.(bool)
+	}
+	return s, nil
+}
 
+func expandBuildStepRunner(localStep map[string]interface{}) (*buildStep, error) {
+	var s api.Step
+	var err error
 	t := localStep["type"].(string)
 	switch t {
 	case "powershell":
-		return expandStepPowershell(localStep)
+		s, err = expandStepPowershell(localStep)
 	case "cmd_line":
-		return expandStepCmdLine(localStep)
+		s, err = expandStepCmdLine(localStep)
 	case "gradle":
-		return expandStepGradle(localStep)
+		s, err = expandStepGradle(localStep)
 	case "docker":
-		return expandStepDocker(localStep)
+		s, err = expandStepDocker(localStep)
+	case "maven":
+		return expandStepMaven(localStep)
+	case "dotnet":
+		return expandStepDotnet(localStep)
+	case "python":
+		return expandStepPython(localStep)
+	case "nodejs":
+		return expandStepNodeJS(localStep)
+	case "kotlin_script":
+		return expandStepKotlin(localStep)
+	case "ssh_exec":
+		return expandStepSSH(localStep, false)
+	case "ssh_upload":
+		return expandStepSSH(localStep, true)
+	case "generic":
+		return expandStepGeneric(localStep)
 	default:
 		return nil, fmt.Errorf("unsupported step type '%s'", t)
 	}
+	if err != nil {
+		return nil, err
+	}
+	return newBuildStep(s)
 }
 
 func expandStepGradle(dt map[string]interface{}) (*api.StepGradle, error) {
@@ -1075,6 +1374,25 @@ func buildVcsRootEntry(raw interface{}) *api.VcsRootEntry {
 	return api.NewVcsRootEntryWithRules(&api.VcsRootReference{ID: localVcs["id"].(string)}, toAttachRules)
 }
 
+// vcsRootEntryAttached reports whether the VCS root is already attached: TeamCity rejects attaching it again
+func vcsRootEntryAttached(dt *api.BuildType, vcsRootID string) bool {
+	for _, e := range dt.VcsRootEntries {
+		if e.ID == vcsRootID {
+			return true
+		}
+	}
+	return false
+}
+
+// attachVcsRootEntry and deleteStep replace their go-teamcity counterparts, which are sent with http.DefaultClient and would skip the provider transport settings
+func attachVcsRootEntry(c *Client, buildTypeID string, entry *api.VcsRootEntry) error {
+	return c.doRequest("POST", buildTypePath(buildTypeID)+"/vcs-root-entries/", entry, nil, "vcs root entry")
+}
+
+func deleteStep(c *Client, buildTypeID string, stepID string) error {
+	return c.doRequest("DELETE", buildTypeItemPath(buildTypeID, "steps", stepID), nil, nil, "build step")
+}
+
 func vcsRootHash(v interface{}) int {
 	raw := v.(map[string]interface{})
 	return schema.HashString(raw["id"].(string))
@@ -1316,3 +1634,20 @@ func resourceBuildConfigInstanceStateUpgradeV0(rawState map[string]interface{},
 
 	return rawState, nil
 }
+
+// resourceBuildConfigInstanceStateUpgradeV1 enables the steps of state written before step.enabled existed,
+// so upgrading doesn't plan an update of every step
+func resourceBuildConfigInstanceStateUpgradeV1(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	steps, _ := rawState["step"].([]interface{})
+	for _, raw := range steps {
+		step, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if enabled, ok := step["enabled"]; !ok || enabled == nil {
+			step["enabled"] = true
+		}
+	}
+
+	return rawState, nil
+}
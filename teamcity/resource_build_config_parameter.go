@@ -0,0 +1,16 @@
+package teamcity
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func resourceBuildConfigParameter() *schema.Resource {
+	owner := parameterOwner{attribute: "build_config_id", kind: "build configuration", path: buildTypePath}
+
+	return resourceParameter(owner, &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+		Description: "The ID of the build configuration or template the parameter is defined in",
+	})
+}
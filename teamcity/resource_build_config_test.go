@@ -516,6 +516,36 @@ func TestAccBuildConfig_UpdateParameters(t *testing.T) {
 	})
 }
 
+func TestAccBuildConfig_PasswordParams(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: TestAccBuildConfigPasswordParams,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "password_params.%", "1"),
+					resource.TestCheckResourceAttr(resName, "password_params.system.signing.key", "key"),
+					resource.TestCheckResourceAttr(resName, "sys_params.%", "0"),
+					resource.TestCheckResourceAttr(resName, "config_params.%", "1"),
+					testAccCheckStoredParameterValue(resName, "id", "system.signing.key", "key"),
+				),
+			},
+			{
+				Config: TestAccBuildConfigParamsUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "password_params.%", "0"),
+					resource.TestCheckResourceAttr(resName, "config_params.github.repository", "updated_repo"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccBuildConfig_Settings(t *testing.T) {
 	var bc api.BuildType
 	resName := "teamcity_build_config.build_configuration_test"
@@ -600,6 +630,16 @@ func TestAccBuildConfig_VcsRoot(t *testing.T) {
 					testAccCheckVcsRootAttached(&bc.VcsRootEntries, "application", "+:*\\n-:README.MD"),
 				),
 			},
+			{
+				// Updating other arguments keeps the VCS roots already attached
+				Config: strings.Replace(TestAccBuildConfigVcsRoot, `name = "build config test"`, `name = "build config test"
+	description = "updated"`, 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "description", "updated"),
+					testAccCheckVcsRootAttached(&bc.VcsRootEntries, "application", "+:*\\n-:README.MD"),
+				),
+			},
 		},
 	})
 }
@@ -642,7 +682,7 @@ func TestAccBuildConfig_AttachTemplates(t *testing.T) {
 
 func testAccCheckStepRemoved(buildTypeID *string, stepRemoved map[string]string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccClient()
 		exists, _ := testStepExists(client, *buildTypeID, stepRemoved)
 		if exists {
 			return fmt.Errorf("expected step %s to be removed, but still exists", stepRemoved["name"])
@@ -653,7 +693,7 @@ func testAccCheckStepRemoved(buildTypeID *string, stepRemoved map[string]string)
 
 func testAccCheckStepExists(buildTypeID *string, stepExpected map[string]string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccClient()
 		_, err := testStepExists(client, *buildTypeID, stepExpected)
 		return err
 	}
@@ -748,13 +788,13 @@ func testAccCheckVcsRootAttached(vcs *[]*api.VcsRootEntry, n string, co string)
 
 func testAccCheckBuildConfigExists(n string, out *api.BuildType) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccClient()
 		return buildConfigExistsHelper(n, s, client, out)
 	}
 }
 
 func updateBuildCounter(buildType *api.BuildType, counter int) {
-	client := testAccProvider.Meta().(*api.Client)
+	client := testAccClient()
 	id := buildType.ID
 
 	bt, err := client.BuildTypes.GetByID(id)
@@ -791,7 +831,7 @@ func buildConfigExistsHelper(n string, s *terraform.State, client *api.Client, o
 }
 
 func testAccCheckBuildConfigDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*api.Client)
+	client := testAccClient()
 	return buildConfigDestroyHelper(s, client)
 }
 
@@ -942,6 +982,25 @@ resource "teamcity_build_config" "build_configuration_test" {
 }
 `
 
+const TestAccBuildConfigPasswordParams = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+
+	config_params = {
+		"github.repository" = "nocode"
+	}
+
+	password_params = {
+		"system.signing.key" = "key"
+	}
+}
+`
+
 const TestAccBuildConfigSettings = `
 resource "teamcity_project" "build_config_project_test" {
   name = "build_config_project_test"
@@ -1176,3 +1235,35 @@ resource teamcity_build_config "build_config" {
 	project_id = "${teamcity_project.child.id}"
 }
 `
+
+func TestBuildConfig_StateUpgradeV1(t *testing.T) {
+	r := testAccProvider.ResourcesMap["teamcity_build_config"]
+	upgrader := r.StateUpgraders[len(r.StateUpgraders)-1]
+	if upgrader.Version != 1 {
+		t.Fatalf("expected the last state upgrader to upgrade version 1, got %d", upgrader.Version)
+	}
+
+	state := map[string]interface{}{
+		"name": "build",
+		"step": []interface{}{
+			map[string]interface{}{"type": "cmd_line", "name": "legacy"},
+			map[string]interface{}{"type": "cmd_line", "name": "disabled", "enabled": false},
+		},
+	}
+	upgraded, err := upgrader.Upgrade(state, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := upgraded["step"].([]interface{})
+	if enabled := steps[0].(map[string]interface{})["enabled"]; enabled != true {
+		t.Errorf("expected step without 'enabled' to be enabled, got %v", enabled)
+	}
+	if enabled := steps[1].(map[string]interface{})["enabled"]; enabled != false {
+		t.Errorf("expected disabled step to stay disabled, got %v", enabled)
+	}
+
+	if _, err := upgrader.Upgrade(map[string]interface{}{"name": "no_steps"}, nil); err != nil {
+		t.Errorf("unexpected error upgrading state without steps: %s", err)
+	}
+}
@@ -0,0 +1,220 @@
+package teamcity
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	api "github.com/leidruid/go-teamcity/teamcity"
+)
+
+func resourceBuildStep() *schema.Resource {
+	return &schema.Resource{
+		Create: createContext(resourceBuildStepCreate),
+		Read:   readContext(resourceBuildStepRead),
+		Update: updateContext(resourceBuildStepUpdate),
+		Delete: deleteContext(resourceBuildStepDelete),
+		Importer: &schema.ResourceImporter{
+			State: resourceBuildStepImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"build_config_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the build configuration or template the step belongs to",
+			},
+			"runner_type": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The TeamCity runner type of the step, e.g. 'simpleRunner' or the ID of a meta-runner",
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"properties": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The runner properties of the step, passed to TeamCity verbatim",
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"position": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      buildStepPositionLast,
+				ValidateFunc: validation.StringInSlice([]string{buildStepPositionFirst, buildStepPositionLast}, false),
+				Description:  "Whether the step is put first or last of the steps of the build configuration when created or when the position changes",
+			},
+		},
+	}
+}
+
+func resourceBuildStepCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	buildConfigID := d.Get("build_config_id").(string)
+	dt := expandStandaloneBuildStep(d)
+
+	buildStepsMutexKV.Lock(buildConfigID)
+	defer buildStepsMutexKV.Unlock(buildConfigID)
+
+	log.Printf("[INFO] Creating build step '%s' in build configuration '%s'", dt.Name, buildConfigID)
+	created, err := client.addBuildStep(buildConfigID, dt)
+	if err != nil {
+		return err
+	}
+	dt.ID = created.ID
+	d.SetId(buildStepID(buildConfigID, dt.ID))
+
+	if err := client.moveBuildStep(buildConfigID, dt, d.Get("position").(string)); err != nil {
+		return err
+	}
+
+	return resourceBuildStepRead(ctx, d, meta)
+}
+
+func resourceBuildStepUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	buildConfigID, stepID, err := parseBuildStepID(d.Id())
+	if err != nil {
+		return err
+	}
+	dt := expandStandaloneBuildStep(d)
+	dt.ID = stepID
+
+	buildStepsMutexKV.Lock(buildConfigID)
+	defer buildStepsMutexKV.Unlock(buildConfigID)
+
+	log.Printf("[INFO] Updating build step '%s'", d.Id())
+	if err := client.updateBuildStep(buildConfigID, dt); err != nil {
+		return err
+	}
+	if d.HasChange("position") {
+		if err := client.moveBuildStep(buildConfigID, dt, d.Get("position").(string)); err != nil {
+			return err
+		}
+	}
+
+	return resourceBuildStepRead(ctx, d, meta)
+}
+
+func resourceBuildStepRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	buildConfigID, stepID, err := parseBuildStepID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	dt, err := client.getBuildStep(buildConfigID, stepID)
+	if err != nil {
+		return handleNotFound(d, client, buildTypeItemPath(buildConfigID, "steps", stepID), err)
+	}
+
+	// TeamCity never returns the values of secure properties, they are kept from state
+	props := make(map[string]interface{})
+	for name, value := range d.Get("properties").(map[string]interface{}) {
+		if strings.HasPrefix(name, "secure:") {
+			props[name] = value
+		}
+	}
+	if dt.Properties != nil {
+		for name, value := range dt.Properties.Map() {
+			if _, ok := props[name]; ok {
+				continue
+			}
+			props[name] = value
+		}
+	}
+
+	if err := d.Set("build_config_id", buildConfigID); err != nil {
+		return err
+	}
+	if err := d.Set("runner_type", dt.Type); err != nil {
+		return err
+	}
+	if err := d.Set("name", dt.Name); err != nil {
+		return err
+	}
+	if err := d.Set("properties", props); err != nil {
+		return err
+	}
+	return d.Set("enabled", !dt.Disabled)
+}
+
+// resourceBuildStepImport sets the position of the imported step, which is only kept in state:
+// first when it precedes the other steps of the build configuration, last otherwise
+func resourceBuildStepImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	buildConfigID, stepID, err := parseBuildStepID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	steps, err := meta.(*Client).getOwnBuildSteps(buildConfigID)
+	if err != nil {
+		return nil, err
+	}
+
+	position := buildStepPositionLast
+	if len(steps) > 1 && steps[0].ID == stepID {
+		position = buildStepPositionFirst
+	}
+	if err := d.Set("position", position); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceBuildStepDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	buildConfigID, stepID, err := parseBuildStepID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	buildStepsMutexKV.Lock(buildConfigID)
+	defer buildStepsMutexKV.Unlock(buildConfigID)
+
+	err = deleteStep(meta.(*Client), buildConfigID, stepID)
+	if isNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func expandStandaloneBuildStep(d *schema.ResourceData) *buildStep {
+	s := &buildStep{
+		Name:       d.Get("name").(string),
+		Type:       d.Get("runner_type").(string),
+		Disabled:   !d.Get("enabled").(bool),
+		Properties: api.NewPropertiesEmpty(),
+	}
+	for name, value := range d.Get("properties").(map[string]interface{}) {
+		s.Properties.AddOrReplaceValue(name, value.(string))
+	}
+	return s
+}
+
+func buildStepID(buildConfigID string, stepID string) string {
+	return fmt.Sprintf("%s/%s", buildConfigID, stepID)
+}
+
+func parseBuildStepID(id string) (buildConfigID string, stepID string, err error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid build step ID '%s', expected '<build_config_id>/<step_id>'", id)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,237 @@
+package teamcity_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	api "github.com/leidruid/go-teamcity/teamcity"
+	"github.com/leidruid/terraform-provider-teamcity/teamcity"
+)
+
+func TestAccBuildStep_Basic(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_step.publish"
+	ids := map[string]string{}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBuildStepConfig(testAccBuildStepBlock, `
+resource "teamcity_build_step" "prepare" {
+	build_config_id = "${teamcity_build_config.build_configuration_test.id}"
+	name = "prepare"
+	runner_type = "simpleRunner"
+	position = "first"
+	properties = {
+		"script.content" = "echo prepare"
+		"use.custom.script" = "true"
+	}
+}
+
+resource "teamcity_build_step" "publish" {
+	build_config_id = "${teamcity_build_config.build_configuration_test.id}"
+	name = "publish"
+	runner_type = "jetbrains.helm"
+	properties = {
+		chart = "charts/app"
+	}
+}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists("teamcity_build_config.build_configuration_test", &bc),
+					testAccCheckBuildStepOrder(&bc.ID, ids, false, "prepare", "build", "test", "publish"),
+					testAccCheckBuildStepProperties(&bc.ID, "publish", "jetbrains.helm", map[string]string{"chart": "charts/app"}),
+					resource.TestCheckResourceAttr("teamcity_build_config.build_configuration_test", "step.#", "2"),
+					resource.TestCheckResourceAttr(resName, "position", "last"),
+					resource.TestCheckResourceAttr(resName, "enabled", "true"),
+					resource.TestCheckResourceAttr(resName, "properties.%", "1"),
+				),
+			},
+			{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// The position of imported steps comes from their place among the steps
+				ResourceName:      "teamcity_build_step.prepare",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccBuildStep_Update(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_step.publish"
+	ids := map[string]string{}
+
+	publish := func(chart string, position string, enabled bool) string {
+		return fmt.Sprintf(`
+resource "teamcity_build_step" "publish" {
+	build_config_id = "${teamcity_build_config.build_configuration_test.id}"
+	name = "publish"
+	runner_type = "jetbrains.helm"
+	position = "%s"
+	enabled = %t
+	properties = {
+		chart = "%s"
+	}
+}
+`, position, enabled, chart)
+	}
+	lint := `
+	step {
+		type = "cmd_line"
+		name = "lint"
+		code = "make lint"
+	}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBuildStepConfig(testAccBuildStepBlock, publish("charts/app", "last", true)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists("teamcity_build_config.build_configuration_test", &bc),
+					testAccCheckBuildStepOrder(&bc.ID, ids, false, "build", "test", "publish"),
+				),
+			},
+			{
+				// Steps added to the step block are kept before the steps positioned last
+				Config: testAccBuildStepConfig(testAccBuildStepBlock+lint, publish("charts/web", "last", false)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildStepOrder(&bc.ID, ids, true, "build", "test", "lint", "publish"),
+					testAccCheckBuildStepDisabled(&bc.ID, "publish", true),
+					testAccCheckBuildStepProperties(&bc.ID, "publish", "jetbrains.helm", map[string]string{"chart": "charts/web"}),
+					resource.TestCheckResourceAttr(resName, "enabled", "false"),
+				),
+			},
+			{
+				Config: testAccBuildStepConfig(testAccBuildStepBlock+lint, publish("charts/web", "first", false)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildStepOrder(&bc.ID, ids, true, "publish", "build", "test", "lint"),
+					resource.TestCheckResourceAttr(resName, "position", "first"),
+				),
+			},
+			{
+				// Removing the step block steps leaves the standalone step alone
+				Config: testAccBuildStepConfig("", publish("charts/web", "first", false)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildStepOrder(&bc.ID, ids, true, "publish"),
+					resource.TestCheckResourceAttr("teamcity_build_config.build_configuration_test", "step.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBuildStep_SecureProperties(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_step.publish"
+	publish := `
+resource "teamcity_build_step" "publish" {
+	build_config_id = "${teamcity_build_config.build_configuration_test.id}"
+	name = "publish"
+	runner_type = "jetbrains.helm"
+	properties = {
+		chart = "charts/app"
+		"secure:registry.password" = "s3cr3t"
+	}
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBuildStepConfig(testAccBuildStepBlock, publish),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists("teamcity_build_config.build_configuration_test", &bc),
+					testAccCheckBuildStepProperties(&bc.ID, "publish", "jetbrains.helm", map[string]string{"chart": "charts/app"}),
+					resource.TestCheckResourceAttr(resName, "properties.secure:registry.password", "s3cr3t"),
+				),
+			},
+			{
+				// Moving the new step before the standalone one would lose the secure value TeamCity does not return
+				Config: testAccBuildStepConfig(testAccBuildStepBlock+`
+	step {
+		type = "cmd_line"
+		name = "lint"
+		code = "make lint"
+	}
+`, publish),
+				ExpectError: regexp.MustCompile("cannot reorder the steps of build configuration"),
+			},
+		},
+	})
+}
+
+func TestAccBuildStep_DeletedOutsideTerraform(t *testing.T) {
+	var bc api.BuildType
+	config := testAccBuildStepConfig(testAccBuildStepBlock, `
+resource "teamcity_build_step" "publish" {
+	build_config_id = "${teamcity_build_config.build_configuration_test.id}"
+	name = "publish"
+	runner_type = "jetbrains.helm"
+}
+`)
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists("teamcity_build_config.build_configuration_test", &bc),
+					testAccDeleteBuildStep("teamcity_build_step.publish"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check:  testAccCheckBuildStepOrder(&bc.ID, map[string]string{}, false, "build", "test", "publish"),
+			},
+		},
+	})
+}
+
+func testAccDeleteBuildStep(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+		return testAccProvider.Meta().(*teamcity.Client).DeleteBuildStep(rs.Primary.ID)
+	}
+}
+
+const testAccBuildStepBlock = `
+	step {
+		type = "cmd_line"
+		name = "build"
+		code = "make build"
+	}
+
+	step {
+		type = "cmd_line"
+		name = "test"
+		code = "make test"
+	}
+`
+
+func testAccBuildStepConfig(steps string, resources string) string {
+	return testAccBuildConfigSteps(steps) + resources
+}
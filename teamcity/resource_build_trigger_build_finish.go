@@ -1,6 +1,7 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -10,12 +11,16 @@ import (
 
 func resourceBuildTriggerBuildFinish() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceBuildTriggerBuildFinishCreate,
-		Read:   resourceBuildTriggerBuildFinishRead,
-		Delete: resourceBuildTriggerBuildFinishDelete,
+		Create: createContext(resourceBuildTriggerBuildFinishCreate),
+		Read:   readContext(resourceBuildTriggerBuildFinishRead),
+		Delete: deleteContext(resourceBuildTriggerBuildFinishDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"build_config_id": {
@@ -44,8 +49,8 @@ func resourceBuildTriggerBuildFinish() *schema.Resource {
 	}
 }
 
-func resourceBuildTriggerBuildFinishCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceBuildTriggerBuildFinishCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	var buildConfigID, triggerBuildConfigID string
 
 	if v, ok := d.GetOk("build_config_id"); ok {
@@ -55,11 +60,11 @@ func resourceBuildTriggerBuildFinishCreate(d *schema.ResourceData, meta interfac
 		triggerBuildConfigID = v.(string)
 	}
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
+	if _, err := getBuildConfiguration(client, buildConfigID); err != nil {
 		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
 	}
 	// validates the Trigger Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(triggerBuildConfigID); err != nil {
+	if _, err := getBuildConfiguration(client, triggerBuildConfigID); err != nil {
 		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", triggerBuildConfigID)
 	}
 
@@ -88,15 +93,15 @@ func resourceBuildTriggerBuildFinishCreate(d *schema.ResourceData, meta interfac
 
 	d.SetId(out.ID())
 
-	return resourceBuildTriggerBuildFinishRead(d, meta)
+	return resourceBuildTriggerBuildFinishRead(ctx, d, meta)
 }
 
-func resourceBuildTriggerBuildFinishRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).TriggerService(d.Get("build_config_id").(string))
+func resourceBuildTriggerBuildFinishRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).TriggerService(d.Get("build_config_id").(string))
 
 	ret, err := getTrigger(client, d.Id())
 	if err != nil {
-		return err
+		return handleNotFound(d, meta.(*Client), buildTypeItemPath(d.Get("build_config_id").(string), "triggers", d.Id()), err)
 	}
 	dt, ok := ret.(*api.TriggerBuildFinish)
 	if !ok {
@@ -122,8 +127,8 @@ func resourceBuildTriggerBuildFinishRead(d *schema.ResourceData, meta interface{
 	return nil
 }
 
-func resourceBuildTriggerBuildFinishDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceBuildTriggerBuildFinishDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	ts := client.TriggerService(d.Get("build_config_id").(string))
 
 	return ts.Delete(d.Id())
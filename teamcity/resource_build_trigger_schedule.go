@@ -1,6 +1,7 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -10,12 +11,16 @@ import (
 
 func resourceBuildTriggerSchedule() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceBuildTriggerScheduleCreate,
-		Read:   resourceBuildTriggerScheduleRead,
-		Delete: resourceBuildTriggerScheduleDelete,
+		Create: createContext(resourceBuildTriggerScheduleCreate),
+		Read:   readContext(resourceBuildTriggerScheduleRead),
+		Delete: deleteContext(resourceBuildTriggerScheduleDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"build_config_id": {
@@ -135,15 +140,15 @@ func resourceBuildTriggerSchedule() *schema.Resource {
 	}
 }
 
-func resourceBuildTriggerScheduleCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceBuildTriggerScheduleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	var buildConfigID string
 
 	if v, ok := d.GetOk("build_config_id"); ok {
 		buildConfigID = v.(string)
 	}
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
+	if _, err := getBuildConfiguration(client, buildConfigID); err != nil {
 		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
 	}
 
@@ -175,15 +180,15 @@ func resourceBuildTriggerScheduleCreate(d *schema.ResourceData, meta interface{}
 
 	d.SetId(out.ID())
 
-	return resourceBuildTriggerScheduleRead(d, meta)
+	return resourceBuildTriggerScheduleRead(ctx, d, meta)
 }
 
-func resourceBuildTriggerScheduleRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).TriggerService(d.Get("build_config_id").(string))
+func resourceBuildTriggerScheduleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).TriggerService(d.Get("build_config_id").(string))
 
 	ret, err := getTrigger(client, d.Id())
 	if err != nil {
-		return err
+		return handleNotFound(d, meta.(*Client), buildTypeItemPath(d.Get("build_config_id").(string), "triggers", d.Id()), err)
 	}
 	dt, ok := ret.(*api.TriggerSchedule)
 	if !ok {
@@ -222,8 +227,8 @@ func resourceBuildTriggerScheduleRead(d *schema.ResourceData, meta interface{})
 	return nil
 }
 
-func resourceBuildTriggerScheduleDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceBuildTriggerScheduleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	ts := client.TriggerService(d.Get("build_config_id").(string))
 
 	return ts.Delete(d.Id())
@@ -19,7 +19,7 @@ func TestAccTeamcityBuildTriggerSchedule_Daily(t *testing.T) {
 		// schedule = "daily"
 		// timezone = "America/Sao Paulo"
 		// hour = 12
-		// minute = 37
+		// minute = 35
 		// rules = ["+:*", "-:*.md"]
 		Steps: []resource.TestStep{
 			resource.TestStep{
@@ -31,7 +31,7 @@ func TestAccTeamcityBuildTriggerSchedule_Daily(t *testing.T) {
 					resource.TestCheckNoResourceAttr(resName, "weekday"),
 					resource.TestCheckResourceAttr(resName, "timezone", "America/Sao Paulo"),
 					resource.TestCheckResourceAttr(resName, "hour", "12"),
-					resource.TestCheckResourceAttr(resName, "minute", "37"),
+					resource.TestCheckResourceAttr(resName, "minute", "35"),
 					resource.TestCheckResourceAttr(resName, "rules.0", "+:*"),
 					resource.TestCheckResourceAttr(resName, "rules.1", "-:*.md"),
 				),
@@ -59,7 +59,7 @@ func TestAccTeamcityBuildTriggerSchedule_DailyUpdate(t *testing.T) {
 					resource.TestCheckNoResourceAttr(resName, "weekday"),
 					resource.TestCheckResourceAttr(resName, "timezone", "America/Sao Paulo"),
 					resource.TestCheckResourceAttr(resName, "hour", "12"),
-					resource.TestCheckResourceAttr(resName, "minute", "37"),
+					resource.TestCheckResourceAttr(resName, "minute", "35"),
 					resource.TestCheckResourceAttr(resName, "rules.0", "+:*"),
 					resource.TestCheckResourceAttr(resName, "rules.1", "-:*.md"),
 				),
@@ -101,7 +101,7 @@ func TestAccTeamcityBuildTriggerSchedule_Weekly(t *testing.T) {
 					resource.TestCheckResourceAttr(resName, "weekday", "Saturday"),
 					resource.TestCheckResourceAttr(resName, "timezone", "America/Sao Paulo"),
 					resource.TestCheckResourceAttr(resName, "hour", "12"),
-					resource.TestCheckResourceAttr(resName, "minute", "37"),
+					resource.TestCheckResourceAttr(resName, "minute", "35"),
 					resource.TestCheckResourceAttr(resName, "rules.0", "+:*"),
 					resource.TestCheckResourceAttr(resName, "rules.1", "-:*.md"),
 				),
@@ -129,7 +129,7 @@ func TestAccTeamcityBuildTriggerSchedule_WeeklyUpdated(t *testing.T) {
 					resource.TestCheckResourceAttr(resName, "weekday", "Saturday"),
 					resource.TestCheckResourceAttr(resName, "timezone", "America/Sao Paulo"),
 					resource.TestCheckResourceAttr(resName, "hour", "12"),
-					resource.TestCheckResourceAttr(resName, "minute", "37"),
+					resource.TestCheckResourceAttr(resName, "minute", "35"),
 					resource.TestCheckResourceAttr(resName, "rules.0", "+:*"),
 					resource.TestCheckResourceAttr(resName, "rules.1", "-:*.md"),
 				),
@@ -284,7 +284,7 @@ resource "teamcity_build_trigger_schedule" "test" {
     schedule = "daily"
     timezone = "America/Sao Paulo"
     hour = 12
-    minute = 37
+    minute = 35
     rules = ["+:*", "-:*.md"]
 }
 `
@@ -326,7 +326,7 @@ resource "teamcity_build_trigger_schedule" "test" {
     schedule = "weekly"
     timezone = "America/Sao Paulo"
     hour = 12
-    minute = 37
+    minute = 35
     weekday = "Saturday"
     rules = ["+:*", "-:*.md"]
 }
@@ -375,7 +375,7 @@ resource "teamcity_build_trigger_schedule" "test" {
     schedule = "daily"
     timezone = "America/Sao Paulo"
     hour = 12
-    minute = 37
+    minute = 35
 	rules = ["+:*", "-:*.md"]
 
 	queue_optimization = true
@@ -419,7 +419,7 @@ resource "teamcity_build_trigger_schedule" "test" {
     schedule = "daily"
     timezone = "America/Sao Paulo"
     hour = 12
-    minute = 37
+    minute = 35
 	rules = ["+:*", "-:*.md"]
 
 	queue_optimization = false
@@ -458,7 +458,7 @@ resource "teamcity_build_trigger_schedule" "test" {
     schedule = "daily"
     timezone = "America/Sao Paulo"
     hour = 12
-    minute = 37
+    minute = 35
 	rules = ["+:*", "-:*.md"]
 }
 `
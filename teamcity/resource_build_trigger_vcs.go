@@ -1,6 +1,7 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -9,12 +10,16 @@ import (
 
 func resourceBuildTriggerVcs() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceBuildTriggerVcsCreate,
-		Read:   resourceBuildTriggerVcsRead,
-		Delete: resourceBuildTriggerVcsDelete,
+		Create: createContext(resourceBuildTriggerVcsCreate),
+		Read:   readContext(resourceBuildTriggerVcsRead),
+		Delete: deleteContext(resourceBuildTriggerVcsDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"build_config_id": {
@@ -38,8 +43,8 @@ func resourceBuildTriggerVcs() *schema.Resource {
 	}
 }
 
-func resourceBuildTriggerVcsCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceBuildTriggerVcsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	var buildConfigID string
 	var err error
 
@@ -47,7 +52,7 @@ func resourceBuildTriggerVcsCreate(d *schema.ResourceData, meta interface{}) err
 		buildConfigID = v.(string)
 	}
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
+	if _, err := getBuildConfiguration(client, buildConfigID); err != nil {
 		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
 	}
 
@@ -74,15 +79,15 @@ func resourceBuildTriggerVcsCreate(d *schema.ResourceData, meta interface{}) err
 
 	d.SetId(out.ID())
 
-	return resourceBuildTriggerVcsRead(d, meta)
+	return resourceBuildTriggerVcsRead(ctx, d, meta)
 }
 
-func resourceBuildTriggerVcsRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).TriggerService(d.Get("build_config_id").(string))
+func resourceBuildTriggerVcsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).TriggerService(d.Get("build_config_id").(string))
 
 	ret, err := getTrigger(client, d.Id())
 	if err != nil {
-		return err
+		return handleNotFound(d, meta.(*Client), buildTypeItemPath(d.Get("build_config_id").(string), "triggers", d.Id()), err)
 	}
 	dt, ok := ret.(*api.TriggerVcs)
 	if !ok {
@@ -108,8 +113,8 @@ func resourceBuildTriggerVcsRead(d *schema.ResourceData, meta interface{}) error
 	return nil
 }
 
-func resourceBuildTriggerVcsDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceBuildTriggerVcsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	ts := client.TriggerService(d.Get("build_config_id").(string))
 
 	return ts.Delete(d.Id())
@@ -66,7 +66,7 @@ func TestAccTeamcityBuildTriggerVcs_Update(t *testing.T) {
 
 func testAccCheckTeamcityBuildTriggerDestroy(bt *string, resourceType string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccClient()
 		return buildTriggerDestroyHelper(s, bt, client, resourceType)
 	}
 }
@@ -94,7 +94,7 @@ func buildTriggerDestroyHelper(s *terraform.State, bt *string, client *api.Clien
 
 func testAccCheckTeamcityBuildTriggerRemoved(buildTypeId *string, t *api.Trigger) resource.TestCheckFunc {
 	return func(S *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccClient()
 
 		_, err := client.TriggerService(*buildTypeId).GetByID((*t).ID())
 		if err != nil {
@@ -109,7 +109,7 @@ func testAccCheckTeamcityBuildTriggerRemoved(buildTypeId *string, t *api.Trigger
 
 func testAccCheckTeamcityBuildTriggerExists(n string, bt *string, t *api.Trigger, exists bool) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccClient()
 
 		found, err := teamcityBuildTriggerExistsHelper(n, bt, s, client, t)
 		if !exists {
@@ -2,6 +2,7 @@ package teamcity
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 
@@ -13,12 +14,16 @@ import (
 
 func resourceFeatureCommitStatusPublisher() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceFeatureCommitStatusPublisherCreate,
-		Read:   resourceFeatureCommitStatusPublisherRead,
-		Delete: resourceFeatureCommitStatusPublisherDelete,
+		Create: createContext(resourceFeatureCommitStatusPublisherCreate),
+		Read:   readContext(resourceFeatureCommitStatusPublisherRead),
+		Delete: deleteContext(resourceFeatureCommitStatusPublisherDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"build_config_id": {
@@ -106,8 +111,8 @@ func resourceFeatureCommitStatusPublisher() *schema.Resource {
 	}
 }
 
-func resourceFeatureCommitStatusPublisherCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeatureCommitStatusPublisherCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	var buildConfigID string
 
 	if v, ok := d.GetOk("build_config_id"); ok {
@@ -115,7 +120,7 @@ func resourceFeatureCommitStatusPublisherCreate(d *schema.ResourceData, meta int
 	}
 
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
+	if _, err := getBuildConfiguration(client, buildConfigID); err != nil {
 		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
 	}
 
@@ -148,15 +153,15 @@ func resourceFeatureCommitStatusPublisherCreate(d *schema.ResourceData, meta int
 		}
 	}
 
-	return resourceFeatureCommitStatusPublisherRead(d, meta)
+	return resourceFeatureCommitStatusPublisherRead(ctx, d, meta)
 }
 
-func resourceFeatureCommitStatusPublisherRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).BuildFeatureService(d.Get("build_config_id").(string))
+func resourceFeatureCommitStatusPublisherRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).BuildFeatureService(d.Get("build_config_id").(string))
 
 	dt, err := getBuildFeatureCommitPublisher(client, d.Id())
 	if err != nil {
-		return err
+		return handleNotFound(d, meta.(*Client), buildTypeItemPath(d.Get("build_config_id").(string), "features", d.Id()), err)
 	}
 
 	if err := d.Set("build_config_id", dt.BuildTypeID()); err != nil {
@@ -179,7 +184,7 @@ func resourceFeatureCommitStatusPublisherRead(d *schema.ResourceData, meta inter
 			return err
 		}
 		optsToSave := resourceReadGithubStatusPublisher(dt)
-		return d.Set("bitbucket_server", optsToSave)
+		return d.Set("github", optsToSave)
 	}
 	return err
 }
@@ -211,8 +216,8 @@ func resourceReadGithubStatusPublisher(dt *api.FeatureCommitStatusPublisher) (op
 	return
 }
 
-func resourceFeatureCommitStatusPublisherDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeatureCommitStatusPublisherDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	svr := client.BuildFeatureService(d.Get("build_config_id").(string))
 
 	return svr.Delete(d.Id())
@@ -2,10 +2,12 @@ package teamcity_test
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 	api "github.com/leidruid/go-teamcity/teamcity"
 )
@@ -35,6 +37,39 @@ func TestAccTeamcityFeatureCommitStatusPublisher_Github(t *testing.T) {
 	})
 }
 
+func TestFeatureCommitStatusPublisher_ReadsGithubOptions(t *testing.T) {
+	p := testProviderWithHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"BUILD_EXT_1","type":"commit-status-publisher","properties":{"count":4,"property":[` +
+			`{"name":"publisherId","value":"githubStatusPublisher"},` +
+			`{"name":"github_host","value":"https://github.example.com/api/v3"},` +
+			`{"name":"github_authentication_type","value":"password"},` +
+			`{"name":"github_username","value":"alice"}]}}`))
+	}))
+
+	r := p.ResourcesMap["teamcity_feature_commit_status_publisher"]
+	d := r.TestResourceData()
+	d.SetId("BUILD_EXT_1")
+	d.Set("build_config_id", "Project1_Build")
+	d.Set("publisher", "github")
+	d.Set("github", []interface{}{map[string]interface{}{"auth_type": "password", "host": "https://api.github.com", "username": "bob"}})
+
+	if err := r.Read(d, p.Meta()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	opts := d.Get("github").(*schema.Set).List()
+	if len(opts) != 1 {
+		t.Fatalf("expected one github block, got %d", len(opts))
+	}
+	if host := opts[0].(map[string]interface{})["host"]; host != "https://github.example.com/api/v3" {
+		t.Errorf("expected github host to be read from TeamCity, got %q", host)
+	}
+	if n := d.Get("bitbucket_server").(*schema.Set).Len(); n != 0 {
+		t.Errorf("expected no bitbucket_server block, got %d", n)
+	}
+}
+
 func TestAccTeamcityFeatureCommitStatusPublisher_GithubUpdate(t *testing.T) {
 	resName := "teamcity_feature_commit_status_publisher.test"
 	var out api.BuildFeature
@@ -72,7 +107,7 @@ func TestAccTeamcityFeatureCommitStatusPublisher_GithubUpdate(t *testing.T) {
 
 func testAccCheckBuildFeatureDestroy(bt *string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccClient()
 		return buildFeatureDestroyHelper(s, bt, client)
 	}
 }
@@ -100,7 +135,7 @@ func buildFeatureDestroyHelper(s *terraform.State, bt *string, client *api.Clien
 
 func testAccCheckBuildFeatureExists(n string, bt *string, out *api.BuildFeature) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccClient()
 		return teamcityBuildFeatureExistsHelper(n, bt, s, client, out)
 	}
 }
@@ -1,6 +1,7 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	api "github.com/leidruid/go-teamcity/teamcity"
@@ -8,12 +9,16 @@ import (
 
 func resourceFeatureDockerSupport() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceFeatureDockerSupportCreate,
-		Read:   resourceFeatureDockerSupportRead,
-		Delete: resourceFeatureDockerSupportDelete,
+		Create: createContext(resourceFeatureDockerSupportCreate),
+		Read:   readContext(resourceFeatureDockerSupportRead),
+		Delete: deleteContext(resourceFeatureDockerSupportDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"build_config_id": {
@@ -25,7 +30,7 @@ func resourceFeatureDockerSupport() *schema.Resource {
 				Type:        schema.TypeString,
 				Required:    true,
 				ForceNew:    true,
-				Description: "Foreign key of Docker Registry Connection",
+				Description: "ID of the Docker Registry connection, e.g. the ID of a teamcity_project_connection with a docker_registry block",
 			},
 			"cleanup": {
 				Type:        schema.TypeBool,
@@ -37,8 +42,8 @@ func resourceFeatureDockerSupport() *schema.Resource {
 	}
 }
 
-func resourceFeatureDockerSupportCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeatureDockerSupportCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	var buildConfigID string
 
 	if v, ok := d.GetOk("build_config_id"); ok {
@@ -46,7 +51,7 @@ func resourceFeatureDockerSupportCreate(d *schema.ResourceData, meta interface{}
 	}
 
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
+	if _, err := getBuildConfiguration(client, buildConfigID); err != nil {
 		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
 	}
 
@@ -63,15 +68,15 @@ func resourceFeatureDockerSupportCreate(d *schema.ResourceData, meta interface{}
 	}
 
 	d.SetId(out.ID())
-	return resourceFeatureDockerSupportRead(d, meta)
+	return resourceFeatureDockerSupportRead(ctx, d, meta)
 }
 
-func resourceFeatureDockerSupportRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).BuildFeatureService(d.Get("build_config_id").(string))
+func resourceFeatureDockerSupportRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).BuildFeatureService(d.Get("build_config_id").(string))
 
 	dt, err := getBuildFeatureDockerSupport(client, d.Id())
 	if err != nil {
-		return err
+		return handleNotFound(d, meta.(*Client), buildTypeItemPath(d.Get("build_config_id").(string), "features", d.Id()), err)
 	}
 
 	if err := d.Set("build_config_id", dt.BuildTypeID()); err != nil {
@@ -87,8 +92,8 @@ func resourceFeatureDockerSupportRead(d *schema.ResourceData, meta interface{})
 	return err
 }
 
-func resourceFeatureDockerSupportDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeatureDockerSupportDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	svr := client.BuildFeatureService(d.Get("build_config_id").(string))
 
 	return svr.Delete(d.Id())
@@ -1,6 +1,7 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
@@ -9,12 +10,16 @@ import (
 
 func resourceFeatureFileContentReplacer() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceFeatureFileContentReplacerCreate,
-		Read:   resourceFeatureFileContentReplacerRead,
-		Delete: resourceFeatureFileContentReplacerDelete,
+		Create: createContext(resourceFeatureFileContentReplacerCreate),
+		Read:   readContext(resourceFeatureFileContentReplacerRead),
+		Delete: deleteContext(resourceFeatureFileContentReplacerDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"build_config_id": {
@@ -83,8 +88,8 @@ func resourceFeatureFileContentReplacer() *schema.Resource {
 	}
 }
 
-func resourceFeatureFileContentReplacerCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeatureFileContentReplacerCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	var buildConfigID string
 
 	if v, ok := d.GetOk("build_config_id"); ok {
@@ -92,7 +97,7 @@ func resourceFeatureFileContentReplacerCreate(d *schema.ResourceData, meta inter
 	}
 
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
+	if _, err := getBuildConfiguration(client, buildConfigID); err != nil {
 		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
 	}
 
@@ -109,15 +114,15 @@ func resourceFeatureFileContentReplacerCreate(d *schema.ResourceData, meta inter
 	}
 
 	d.SetId(out.ID())
-	return resourceFeatureFileContentReplacerRead(d, meta)
+	return resourceFeatureFileContentReplacerRead(ctx, d, meta)
 }
 
-func resourceFeatureFileContentReplacerRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).BuildFeatureService(d.Get("build_config_id").(string))
+func resourceFeatureFileContentReplacerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).BuildFeatureService(d.Get("build_config_id").(string))
 
 	dt, err := getBuildFeatureFileContentReplacer(client, d.Id())
 	if err != nil {
-		return err
+		return handleNotFound(d, meta.(*Client), buildTypeItemPath(d.Get("build_config_id").(string), "features", d.Id()), err)
 	}
 
 	if err := d.Set("build_config_id", dt.BuildTypeID()); err != nil {
@@ -139,8 +144,8 @@ func resourceFeatureFileContentReplacerRead(d *schema.ResourceData, meta interfa
 	return err
 }
 
-func resourceFeatureFileContentReplacerDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeatureFileContentReplacerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	svr := client.BuildFeatureService(d.Get("build_config_id").(string))
 
 	return svr.Delete(d.Id())
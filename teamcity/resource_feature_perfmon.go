@@ -1,6 +1,7 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	api "github.com/leidruid/go-teamcity/teamcity"
@@ -8,12 +9,16 @@ import (
 
 func resourceFeaturePerformanceMonitor() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceFeaturePerformanceMonitorCreate,
-		Read:   resourceFeaturePerformanceMonitorRead,
-		Delete: resourceFeaturePerformanceMonitorDelete,
+		Create: createContext(resourceFeaturePerformanceMonitorCreate),
+		Read:   readContext(resourceFeaturePerformanceMonitorRead),
+		Delete: deleteContext(resourceFeaturePerformanceMonitorDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"build_config_id": {
@@ -25,8 +30,8 @@ func resourceFeaturePerformanceMonitor() *schema.Resource {
 	}
 }
 
-func resourceFeaturePerformanceMonitorCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeaturePerformanceMonitorCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	var buildConfigID string
 
 	if v, ok := d.GetOk("build_config_id"); ok {
@@ -34,7 +39,7 @@ func resourceFeaturePerformanceMonitorCreate(d *schema.ResourceData, meta interf
 	}
 
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
+	if _, err := getBuildConfiguration(client, buildConfigID); err != nil {
 		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
 	}
 
@@ -48,15 +53,15 @@ func resourceFeaturePerformanceMonitorCreate(d *schema.ResourceData, meta interf
 	}
 
 	d.SetId(out.ID())
-	return resourceFeaturePerformanceMonitorRead(d, meta)
+	return resourceFeaturePerformanceMonitorRead(ctx, d, meta)
 }
 
-func resourceFeaturePerformanceMonitorRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).BuildFeatureService(d.Get("build_config_id").(string))
+func resourceFeaturePerformanceMonitorRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).BuildFeatureService(d.Get("build_config_id").(string))
 
 	dt, err := getBuildFeaturePerformanceMonitor(client, d.Id())
 	if err != nil {
-		return err
+		return handleNotFound(d, meta.(*Client), buildTypeItemPath(d.Get("build_config_id").(string), "features", d.Id()), err)
 	}
 
 	if err := d.Set("build_config_id", dt.BuildTypeID()); err != nil {
@@ -66,8 +71,8 @@ func resourceFeaturePerformanceMonitorRead(d *schema.ResourceData, meta interfac
 	return err
 }
 
-func resourceFeaturePerformanceMonitorDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeaturePerformanceMonitorDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	svr := client.BuildFeatureService(d.Get("build_config_id").(string))
 
 	return svr.Delete(d.Id())
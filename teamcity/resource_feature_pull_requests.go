@@ -2,6 +2,7 @@ package teamcity
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -11,12 +12,16 @@ import (
 
 func resourceFeaturePullRequests() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceFeaturePullRequestsCreate,
-		Read:   resourceFeaturePullRequestsRead,
-		Delete: resourceFeaturePullRequestsDelete,
+		Create: createContext(resourceFeaturePullRequestsCreate),
+		Read:   readContext(resourceFeaturePullRequestsRead),
+		Delete: deleteContext(resourceFeaturePullRequestsDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"build_config_id": {
@@ -80,8 +85,8 @@ func resourceFeaturePullRequests() *schema.Resource {
 	}
 }
 
-func resourceFeaturePullRequestsCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeaturePullRequestsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	var buildConfigID string
 
 	if v, ok := d.GetOk("build_config_id"); ok {
@@ -89,7 +94,7 @@ func resourceFeaturePullRequestsCreate(d *schema.ResourceData, meta interface{})
 	}
 
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
+	if _, err := getBuildConfiguration(client, buildConfigID); err != nil {
 		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
 	}
 
@@ -112,15 +117,15 @@ func resourceFeaturePullRequestsCreate(d *schema.ResourceData, meta interface{})
 		}
 	}
 
-	return resourceFeaturePullRequestsRead(d, meta)
+	return resourceFeaturePullRequestsRead(ctx, d, meta)
 }
 
-func resourceFeaturePullRequestsRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).BuildFeatureService(d.Get("build_config_id").(string))
+func resourceFeaturePullRequestsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).BuildFeatureService(d.Get("build_config_id").(string))
 
 	dt, err := getBuildFeaturePullRequests(client, d.Id())
 	if err != nil {
-		return err
+		return handleNotFound(d, meta.(*Client), buildTypeItemPath(d.Get("build_config_id").(string), "features", d.Id()), err)
 	}
 
 	if err := d.Set("build_config_id", dt.BuildTypeID()); err != nil {
@@ -156,8 +161,8 @@ func resourceReadPullRequests(dt *api.FeaturePullRequests) (optsToSave []map[str
 	return
 }
 
-func resourceFeaturePullRequestsDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeaturePullRequestsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	svr := client.BuildFeatureService(d.Get("build_config_id").(string))
 
 	return svr.Delete(d.Id())
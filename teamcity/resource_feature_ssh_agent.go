@@ -1,6 +1,7 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	api "github.com/leidruid/go-teamcity/teamcity"
@@ -8,12 +9,16 @@ import (
 
 func resourceFeatureSshAgent() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceFeatureSshAgentCreate,
-		Read:   resourceFeatureSshAgentRead,
-		Delete: resourceFeatureSshAgentDelete,
+		Create: createContext(resourceFeatureSshAgentCreate),
+		Read:   readContext(resourceFeatureSshAgentRead),
+		Delete: deleteContext(resourceFeatureSshAgentDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"build_config_id": {
@@ -30,8 +35,8 @@ func resourceFeatureSshAgent() *schema.Resource {
 	}
 }
 
-func resourceFeatureSshAgentCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeatureSshAgentCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	var buildConfigID string
 
 	if v, ok := d.GetOk("build_config_id"); ok {
@@ -39,7 +44,7 @@ func resourceFeatureSshAgentCreate(d *schema.ResourceData, meta interface{}) err
 	}
 
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
+	if _, err := getBuildConfiguration(client, buildConfigID); err != nil {
 		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
 	}
 
@@ -56,15 +61,15 @@ func resourceFeatureSshAgentCreate(d *schema.ResourceData, meta interface{}) err
 	}
 
 	d.SetId(out.ID())
-	return resourceFeatureSshAgentRead(d, meta)
+	return resourceFeatureSshAgentRead(ctx, d, meta)
 }
 
-func resourceFeatureSshAgentRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).BuildFeatureService(d.Get("build_config_id").(string))
+func resourceFeatureSshAgentRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).BuildFeatureService(d.Get("build_config_id").(string))
 
 	dt, err := getBuildFeatureSshAgent(client, d.Id())
 	if err != nil {
-		return err
+		return handleNotFound(d, meta.(*Client), buildTypeItemPath(d.Get("build_config_id").(string), "features", d.Id()), err)
 	}
 
 	if err := d.Set("build_config_id", dt.BuildTypeID()); err != nil {
@@ -79,8 +84,8 @@ func resourceFeatureSshAgentRead(d *schema.ResourceData, meta interface{}) error
 	return err
 }
 
-func resourceFeatureSshAgentDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeatureSshAgentDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	svr := client.BuildFeatureService(d.Get("build_config_id").(string))
 
 	return svr.Delete(d.Id())
@@ -1,6 +1,7 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	api "github.com/leidruid/go-teamcity/teamcity"
@@ -9,12 +10,16 @@ import (
 
 func resourceFeatureVcsLabeling() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceFeatureVcsLabelingCreate,
-		Read:   resourceFeatureVcsLabelingRead,
-		Delete: resourceFeatureVcsLabelingDelete,
+		Create: createContext(resourceFeatureVcsLabelingCreate),
+		Read:   readContext(resourceFeatureVcsLabelingRead),
+		Delete: deleteContext(resourceFeatureVcsLabelingDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"build_config_id": {
@@ -48,8 +53,8 @@ func resourceFeatureVcsLabeling() *schema.Resource {
 	}
 }
 
-func resourceFeatureVcsLabelingCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeatureVcsLabelingCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	var buildConfigID string
 
 	if v, ok := d.GetOk("build_config_id"); ok {
@@ -57,7 +62,7 @@ func resourceFeatureVcsLabelingCreate(d *schema.ResourceData, meta interface{})
 	}
 
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
+	if _, err := getBuildConfiguration(client, buildConfigID); err != nil {
 		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
 	}
 
@@ -74,15 +79,15 @@ func resourceFeatureVcsLabelingCreate(d *schema.ResourceData, meta interface{})
 	}
 
 	d.SetId(out.ID())
-	return resourceFeatureVcsLabelingRead(d, meta)
+	return resourceFeatureVcsLabelingRead(ctx, d, meta)
 }
 
-func resourceFeatureVcsLabelingRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).BuildFeatureService(d.Get("build_config_id").(string))
+func resourceFeatureVcsLabelingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).BuildFeatureService(d.Get("build_config_id").(string))
 
 	dt, err := getBuildFeatureVcsLabeling(client, d.Id())
 	if err != nil {
-		return err
+		return handleNotFound(d, meta.(*Client), buildTypeItemPath(d.Get("build_config_id").(string), "features", d.Id()), err)
 	}
 
 	if err := d.Set("build_config_id", dt.BuildTypeID()); err != nil {
@@ -100,8 +105,8 @@ func resourceFeatureVcsLabelingRead(d *schema.ResourceData, meta interface{}) er
 	return err
 }
 
-func resourceFeatureVcsLabelingDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeatureVcsLabelingDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	svr := client.BuildFeatureService(d.Get("build_config_id").(string))
 
 	return svr.Delete(d.Id())
@@ -1,6 +1,7 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 	"hash/crc32"
 	"regexp"
@@ -12,11 +13,15 @@ import (
 
 func resourceGroup() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceGroupCreate,
-		Read:   resourceGroupRead,
-		Delete: resourceGroupDelete,
+		Create: createContext(resourceGroupCreate),
+		Read:   readContext(resourceGroupRead),
+		Delete: deleteContext(resourceGroupDelete),
 		Importer: &schema.ResourceImporter{
-			State: resourceGroupImport,
+			State: importStateContext(resourceGroupImport),
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -40,8 +45,8 @@ func resourceGroup() *schema.Resource {
 	}
 }
 
-func resourceGroupCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	var key, name, description string
 
 	if v, ok := d.GetOk("key"); ok {
@@ -78,7 +83,7 @@ func resourceGroupCreate(d *schema.ResourceData, meta interface{}) error {
 	d.MarkNewResource()
 	d.SetId(created.Key)
 
-	return resourceGroupRead(d, meta)
+	return resourceGroupRead(ctx, d, meta)
 }
 
 func generateKey(name string) (*string, error) {
@@ -93,16 +98,12 @@ func generateKey(name string) (*string, error) {
 	return &generatedKey, nil
 }
 
-func resourceGroupRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 
 	dt, err := client.Groups.GetByKey(d.Id())
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
-			d.SetId("")
-			return nil
-		}
-		return err
+		return handleNotFound(d, client, groupPath(d.Id()), err)
 	}
 	if err := d.Set("key", dt.Key); err != nil {
 		return err
@@ -117,14 +118,14 @@ func resourceGroupRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func resourceGroupDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 
 	return client.Groups.Delete(d.Id())
 }
 
-func resourceGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceGroupRead(d, meta); err != nil {
+func resourceGroupImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := resourceGroupRead(ctx, d, meta); err != nil {
 		return nil, err
 	}
 	return []*schema.ResourceData{d}, nil
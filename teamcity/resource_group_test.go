@@ -75,7 +75,7 @@ func generateKey(name string) string {
 }
 
 func testAccCheckGroupDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*api.Client)
+	client := testAccClient()
 	return buildGroupDestroyHelper(s, client)
 }
 
@@ -101,7 +101,7 @@ func buildGroupDestroyHelper(s *terraform.State, client *api.Client) error {
 
 func testAccCheckGroupExists(n string, out *api.Group) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccClient()
 		return groupExistsHelper(n, s, client, out)
 	}
 }
@@ -1,6 +1,7 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	api "github.com/leidruid/go-teamcity/teamcity"
@@ -9,12 +10,17 @@ import (
 
 func resourceProject() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceProjectCreate,
-		Read:   resourceProjectRead,
-		Update: resourceProjectUpdate,
-		Delete: resourceProjectDelete,
+		Create: createContext(resourceProjectCreate),
+		Read:   readContext(resourceProjectRead),
+		Update: updateContext(resourceProjectUpdate),
+		Delete: deleteContext(resourceProjectDelete),
 		Importer: &schema.ResourceImporter{
-			State: resourceProjectImport,
+			State: importStateContext(resourceProjectImport),
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -44,12 +50,23 @@ func resourceProject() *schema.Resource {
 				Type:     schema.TypeMap,
 				Optional: true,
 			},
+			"password_params": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "A map of password parameters, by full name including the 'system.' or 'env.' prefix. Values are write-only: TeamCity never returns them",
+			},
+			"password_rotation_trigger": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Arbitrary value: changing it writes all password_params again, e.g. after they were changed outside Terraform",
+			},
 		},
 	}
 }
 
-func resourceProjectCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	var name, parentID string
 
 	if v, ok := d.GetOk("name"); ok {
@@ -75,11 +92,11 @@ func resourceProjectCreate(d *schema.ResourceData, meta interface{}) error {
 	d.MarkNewResource()
 	d.SetId(created.ID)
 
-	return resourceProjectUpdate(d, client)
+	return resourceProjectUpdate(ctx, d, client)
 }
 
-func resourceProjectUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceProjectUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	dt, err := client.Projects.GetByID(d.Id())
 	if err != nil {
 		return err
@@ -95,24 +112,25 @@ func resourceProjectUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
-	dt.Parameters, err = expandParameterCollection(d)
-	if err != nil {
-		return err
-	}
+	// Parameters are updated one at a time, since go-teamcity replaces all of them, dropping their specs
+	dt.Parameters = api.NewParametersEmpty()
 
 	_, err = client.Projects.Update(dt)
 	if err != nil {
 		return nil
 	}
-	return resourceProjectRead(d, meta)
+	if err := updateParameterCollection(d, client, projectPath(d.Id())); err != nil {
+		return err
+	}
+	return resourceProjectRead(ctx, d, meta)
 }
 
-func resourceProjectRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceProjectRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 
-	dt, err := getProject(client, d.Id())
+	dt, err := getProject(client.Client, d.Id())
 	if err != nil {
-		return err
+		return handleNotFound(d, client, projectPath(d.Id()), err)
 	}
 	if err := d.Set("name", dt.Name); err != nil {
 		return err
@@ -124,20 +142,23 @@ func resourceProjectRead(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
-	flattenParameterCollection(d, dt.Parameters)
-	return nil
+	params, err := client.getParameters(projectPath(d.Id()))
+	if err != nil {
+		return err
+	}
+	return flattenParameterCollection(d, params)
 }
 
-func resourceProjectDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceProjectDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	log.Print(fmt.Sprintf("[DEBUG]: resourceProjectDelete - Destroying project %v", d.Id()))
 	err := client.Projects.Delete(d.Id())
 	log.Print(fmt.Sprintf("[INFO]: resourceProjectDelete - Destroyed project %v", d.Id()))
 	return err
 }
 
-func resourceProjectImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceProjectRead(d, meta); err != nil {
+func resourceProjectImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := resourceProjectRead(ctx, d, meta); err != nil {
 		return nil, err
 	}
 	return []*schema.ResourceData{d}, nil
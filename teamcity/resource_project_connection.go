@@ -0,0 +1,474 @@
+package teamcity
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	api "github.com/leidruid/go-teamcity/teamcity"
+)
+
+// projectFeatureTypeOAuthProvider is the project feature type TeamCity stores connections as
+const projectFeatureTypeOAuthProvider = "OAuthProvider"
+
+// projectConnectionType describes how a connection block maps to the properties of the project feature
+type projectConnectionType struct {
+	providerType string
+	// fields maps the block arguments to feature properties. Values of "secure:" properties are never returned by TeamCity.
+	fields map[string]string
+	// selfHosted is set for providers using another providerType for self-hosted servers, selected by the 'url' argument
+	selfHosted *projectConnectionSelfHosted
+}
+
+type projectConnectionSelfHosted struct {
+	cloudURL     string
+	providerType string
+	urlProperty  string
+}
+
+var projectConnectionTypes = map[string]projectConnectionType{
+	"docker_registry": {
+		providerType: "Docker",
+		fields: map[string]string{
+			"url":      "repositoryUrl",
+			"username": "userName",
+			"password": "secure:userPass",
+		},
+	},
+	"github": {
+		providerType: "GitHub",
+		fields: map[string]string{
+			"client_id":     "clientId",
+			"client_secret": "secure:clientSecret",
+		},
+		selfHosted: &projectConnectionSelfHosted{cloudURL: "https://github.com", providerType: "GHE", urlProperty: "gitHubUrl"},
+	},
+	"github_app": {
+		providerType: "GitHubApp",
+		fields: map[string]string{
+			"url":            "gitHubUrl",
+			"app_id":         "gitHubApp.appId",
+			"client_id":      "gitHubApp.clientId",
+			"client_secret":  "secure:gitHubApp.clientSecret",
+			"private_key":    "secure:gitHubApp.privateKey",
+			"webhook_secret": "secure:gitHubApp.webhookSecret",
+			"owner_url":      "gitHubApp.ownerUrl",
+		},
+	},
+	"gitlab": {
+		providerType: "GitLabCom",
+		fields: map[string]string{
+			"application_id": "clientId",
+			"secret":         "secure:clientSecret",
+		},
+		selfHosted: &projectConnectionSelfHosted{cloudURL: "https://gitlab.com", providerType: "GitLabCEorEE", urlProperty: "gitLabUrl"},
+	},
+	"bitbucket_cloud": {
+		providerType: "BitBucketCloud",
+		fields: map[string]string{
+			"key":    "clientId",
+			"secret": "secure:clientSecret",
+		},
+	},
+	"bitbucket_server": {
+		providerType: "BitbucketServer",
+		fields: map[string]string{
+			"url":           "bitbucketUrl",
+			"client_id":     "clientId",
+			"client_secret": "secure:clientSecret",
+		},
+	},
+	"azure_devops": {
+		providerType: "AzureDevOps",
+		fields: map[string]string{
+			"url":          "serverUrl",
+			"access_token": "secure:accessToken",
+		},
+	},
+	"slack": {
+		providerType: "slackConnection",
+		fields: map[string]string{
+			"client_id":     "clientId",
+			"client_secret": "secure:clientSecret",
+			"bot_token":     "secure:token",
+		},
+	},
+}
+
+var projectConnectionBlocks = []string{"docker_registry", "github", "github_app", "gitlab", "bitbucket_cloud", "bitbucket_server", "azure_devops", "slack"}
+
+func resourceProjectConnection() *schema.Resource {
+	return &schema.Resource{
+		Create: createContext(resourceProjectConnectionCreate),
+		Read:   readContext(resourceProjectConnectionRead),
+		Update: updateContext(resourceProjectConnectionUpdate),
+		Delete: deleteContext(resourceProjectConnectionDelete),
+		Importer: &schema.ResourceImporter{
+			State: resourceProjectConnectionImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the project the connection belongs to. The connection is available to the project and its subprojects.",
+			},
+			"display_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the connection shown in TeamCity",
+			},
+			"docker_registry": projectConnectionBlock("Connection to a Docker registry", map[string]*schema.Schema{
+				"url": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Default:     "https://docker.io",
+					Description: "Address of the registry",
+				},
+				"username": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"password": {
+					Type:      schema.TypeString,
+					Optional:  true,
+					Sensitive: true,
+				},
+			}),
+			"github": projectConnectionBlock("Connection to a GitHub OAuth App", map[string]*schema.Schema{
+				"url": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Default:     "https://github.com",
+					Description: "URL of the GitHub server. Any other value than https://github.com creates a GitHub Enterprise connection.",
+				},
+				"client_id": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"client_secret": {
+					Type:      schema.TypeString,
+					Required:  true,
+					Sensitive: true,
+				},
+			}),
+			"github_app": projectConnectionBlock("Connection to a GitHub App", map[string]*schema.Schema{
+				"url": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Default:     "https://github.com",
+					Description: "URL of the GitHub server",
+				},
+				"app_id": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"client_id": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"client_secret": {
+					Type:      schema.TypeString,
+					Required:  true,
+					Sensitive: true,
+				},
+				"private_key": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Sensitive:   true,
+					Description: "Private key of the app, in PEM format",
+				},
+				"webhook_secret": {
+					Type:      schema.TypeString,
+					Optional:  true,
+					Sensitive: true,
+				},
+				"owner_url": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "URL of the user or organization owning the app, e.g. https://github.com/example",
+				},
+			}),
+			"gitlab": projectConnectionBlock("Connection to a GitLab application", map[string]*schema.Schema{
+				"url": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Default:     "https://gitlab.com",
+					Description: "URL of the GitLab server. Any other value than https://gitlab.com creates a GitLab CE/EE connection.",
+				},
+				"application_id": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"secret": {
+					Type:      schema.TypeString,
+					Required:  true,
+					Sensitive: true,
+				},
+			}),
+			"bitbucket_cloud": projectConnectionBlock("Connection to a Bitbucket Cloud OAuth consumer", map[string]*schema.Schema{
+				"key": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"secret": {
+					Type:      schema.TypeString,
+					Required:  true,
+					Sensitive: true,
+				},
+			}),
+			"bitbucket_server": projectConnectionBlock("Connection to a Bitbucket Server or Data Center application link", map[string]*schema.Schema{
+				"url": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"client_id": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"client_secret": {
+					Type:      schema.TypeString,
+					Required:  true,
+					Sensitive: true,
+				},
+			}),
+			"azure_devops": projectConnectionBlock("Connection to Azure DevOps using a personal access token", map[string]*schema.Schema{
+				"url": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "URL of the organization or collection, e.g. https://dev.azure.com/example",
+				},
+				"access_token": {
+					Type:      schema.TypeString,
+					Required:  true,
+					Sensitive: true,
+				},
+			}),
+			"slack": projectConnectionBlock("Connection to a Slack app", map[string]*schema.Schema{
+				"client_id": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"client_secret": {
+					Type:      schema.TypeString,
+					Required:  true,
+					Sensitive: true,
+				},
+				"bot_token": {
+					Type:      schema.TypeString,
+					Required:  true,
+					Sensitive: true,
+				},
+			}),
+		},
+	}
+}
+
+func projectConnectionBlock(description string, fields map[string]*schema.Schema) *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeList,
+		Optional:     true,
+		MaxItems:     1,
+		ExactlyOneOf: projectConnectionBlocks,
+		Description:  description,
+		Elem: &schema.Resource{
+			Schema: fields,
+		},
+	}
+}
+
+// projectFeature is a project feature, as returned by the projects/{id}/projectFeatures endpoint
+type projectFeature struct {
+	ID         string          `json:"id,omitempty"`
+	Type       string          `json:"type"`
+	Properties *api.Properties `json:"properties,omitempty"`
+}
+
+func projectFeaturePath(projectID string, id string) string {
+	return fmt.Sprintf("%s/projectFeatures/%s", projectPath(projectID), url.PathEscape(id))
+}
+
+func (c *Client) createProjectFeature(projectID string, dt *projectFeature) (*projectFeature, error) {
+	var out projectFeature
+	if err := c.doRequest("POST", fmt.Sprintf("%s/projectFeatures", projectPath(projectID)), dt, &out, "project feature"); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) getProjectFeature(projectID string, id string) (*projectFeature, error) {
+	var out projectFeature
+	if err := c.doRequest("GET", projectFeaturePath(projectID, id), nil, &out, "project feature"); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) updateProjectFeatureProperties(projectID string, id string, props *api.Properties) error {
+	return c.doRequest("PUT", projectFeaturePath(projectID, id)+"/properties", props, nil, "project feature properties")
+}
+
+func (c *Client) deleteProjectFeature(projectID string, id string) error {
+	return c.doRequest("DELETE", projectFeaturePath(projectID, id), nil, nil, "project feature")
+}
+
+func resourceProjectConnectionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	log.Printf("[INFO] detected new project connection resource, creating.")
+	created, err := client.createProjectFeature(d.Get("project_id").(string), &projectFeature{
+		Type:       projectFeatureTypeOAuthProvider,
+		Properties: expandProjectConnectionProperties(d),
+	})
+	if err != nil {
+		return err
+	}
+	d.SetId(created.ID)
+
+	return resourceProjectConnectionRead(ctx, d, meta)
+}
+
+func resourceProjectConnectionUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	log.Printf("[INFO] Updating project connection resource.")
+	if err := client.updateProjectFeatureProperties(d.Get("project_id").(string), d.Id(), expandProjectConnectionProperties(d)); err != nil {
+		return err
+	}
+
+	return resourceProjectConnectionRead(ctx, d, meta)
+}
+
+func resourceProjectConnectionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	projectID := d.Get("project_id").(string)
+
+	dt, err := client.getProjectFeature(projectID, d.Id())
+	if err != nil {
+		return handleNotFound(d, client, projectFeaturePath(projectID, d.Id()), err)
+	}
+	if dt.Type != projectFeatureTypeOAuthProvider {
+		return fmt.Errorf("project feature with ID = %s is not a connection. Actual type: %s", d.Id(), dt.Type)
+	}
+
+	props := map[string]string{}
+	if dt.Properties != nil {
+		props = dt.Properties.Map()
+	}
+
+	block, connection, ok := findProjectConnectionType(props["providerType"])
+	if !ok {
+		return fmt.Errorf("connection with ID = %s has an unsupported providerType '%s'", d.Id(), props["providerType"])
+	}
+
+	if err := d.Set("display_name", props["displayName"]); err != nil {
+		return err
+	}
+	for _, name := range projectConnectionBlocks {
+		var v []map[string]interface{}
+		if name == block {
+			v = flattenProjectConnection(d, block, connection, props)
+		}
+		if err := d.Set(name, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func resourceProjectConnectionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	err := meta.(*Client).deleteProjectFeature(d.Get("project_id").(string), d.Id())
+	if isNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// resourceProjectConnectionImport accepts IDs in the form of <project_id>/<connection_id>, since connection IDs are only unique within a project
+func resourceProjectConnectionImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid connection import ID '%s', expected '<project_id>/<connection_id>'", d.Id())
+	}
+
+	if err := d.Set("project_id", parts[0]); err != nil {
+		return nil, err
+	}
+	d.SetId(parts[1])
+	return []*schema.ResourceData{d}, nil
+}
+
+func findProjectConnectionType(providerType string) (string, projectConnectionType, bool) {
+	for name, t := range projectConnectionTypes {
+		if t.providerType == providerType || (t.selfHosted != nil && t.selfHosted.providerType == providerType) {
+			return name, t, true
+		}
+	}
+	return "", projectConnectionType{}, false
+}
+
+func expandProjectConnectionProperties(d *schema.ResourceData) *api.Properties {
+	props := api.NewPropertiesEmpty()
+	props.AddOrReplaceValue("displayName", d.Get("display_name").(string))
+
+	for _, name := range projectConnectionBlocks {
+		v, ok := d.GetOk(name)
+		if !ok || v.([]interface{})[0] == nil {
+			continue
+		}
+		block := v.([]interface{})[0].(map[string]interface{})
+		connection := projectConnectionTypes[name]
+
+		providerType := connection.providerType
+		if sh := connection.selfHosted; sh != nil {
+			if u := block["url"].(string); strings.TrimSuffix(u, "/") != sh.cloudURL {
+				providerType = sh.providerType
+				props.AddOrReplaceValue(sh.urlProperty, u)
+			}
+		}
+		props.AddOrReplaceValue("providerType", providerType)
+
+		for arg, property := range connection.fields {
+			setProperty(props, property, block[arg].(string))
+		}
+	}
+
+	return props
+}
+
+func flattenProjectConnection(d *schema.ResourceData, block string, connection projectConnectionType, props map[string]string) []map[string]interface{} {
+	m := make(map[string]interface{})
+
+	for arg, property := range connection.fields {
+		if strings.HasPrefix(property, "secure:") {
+			//Set back secure values if contained in state, since TeamCity doesn't return them
+			if v, ok := d.GetOk(fmt.Sprintf("%s.0.%s", block, arg)); ok {
+				m[arg] = v.(string)
+			}
+			continue
+		}
+		m[arg] = props[property]
+	}
+
+	if sh := connection.selfHosted; sh != nil {
+		if props["providerType"] == sh.providerType {
+			m["url"] = props[sh.urlProperty]
+		} else if v, ok := d.GetOk(block + ".0.url"); ok && strings.TrimSuffix(v.(string), "/") == sh.cloudURL {
+			m["url"] = v.(string)
+		} else {
+			m["url"] = sh.cloudURL
+		}
+	}
+
+	return []map[string]interface{}{m}
+}
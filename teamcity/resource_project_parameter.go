@@ -0,0 +1,16 @@
+package teamcity
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func resourceProjectParameter() *schema.Resource {
+	owner := parameterOwner{attribute: "project_id", kind: "project", path: projectPath}
+
+	return resourceParameter(owner, &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+		Description: "The ID of the project the parameter is defined in",
+	})
+}
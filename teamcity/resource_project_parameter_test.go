@@ -0,0 +1,466 @@
+package teamcity_test
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/leidruid/terraform-provider-teamcity/teamcity"
+)
+
+func TestAccProjectParameter_Select(t *testing.T) {
+	resName := "teamcity_project_parameter.environment"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckProjectParameterDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccProjectParameterSelect,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckParameterSpec(resName, "staging",
+						"select data_1='staging' data_2='production' description='Where to deploy, e.g. |'staging|'' display='prompt' label='Environment' label_2='Production |[live|]'"),
+					resource.TestCheckResourceAttr(resName, "id", "ParameterProject/deploy.environment"),
+					resource.TestCheckResourceAttr(resName, "option.#", "2"),
+					resource.TestCheckResourceAttr(resName, "option.1.label", "Production [live]"),
+				),
+			},
+			resource.TestStep{
+				Config:             testAccProjectParameterSelect,
+				ExpectNonEmptyPlan: false,
+			},
+			resource.TestStep{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccProjectParameter_Password(t *testing.T) {
+	resName := "teamcity_project_parameter.token"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckProjectParameterDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccProjectParameterPassword("1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckParameterSpec(resName, "", "password display='hidden'"),
+					testAccCheckStoredParameterValue(resName, "project_id", "env.DEPLOY_TOKEN", "s3cr3t"),
+					resource.TestCheckResourceAttr(resName, "password_value", "s3cr3t"),
+					resource.TestCheckResourceAttr(resName, "value", ""),
+				),
+			},
+			resource.TestStep{
+				PreConfig: func() { testAccSetStoredParameterValue("ParameterProject", "env.DEPLOY_TOKEN", "changed in the UI") },
+				Config:    testAccProjectParameterPassword("1"),
+				Check:     testAccCheckStoredParameterValue(resName, "project_id", "env.DEPLOY_TOKEN", "changed in the UI"),
+			},
+			resource.TestStep{
+				Config: testAccProjectParameterPassword("2"),
+				Check:  testAccCheckStoredParameterValue(resName, "project_id", "env.DEPLOY_TOKEN", "s3cr3t"),
+			},
+			resource.TestStep{
+				ResourceName:            resName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password_value", "rotation_trigger"},
+			},
+		},
+	})
+}
+
+func TestAccProjectParameter_Update(t *testing.T) {
+	resName := "teamcity_project_parameter.java_home"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckProjectParameterDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccProjectParameterText("/usr/lib/jvm/java-11", ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckParameterSpec(resName, "/usr/lib/jvm/java-11", "text display='normal' validationMode='any'"),
+					resource.TestCheckResourceAttr(resName, "id", "ParameterProject/env.JAVA_HOME"),
+					resource.TestCheckResourceAttr(resName, "kind", "environment"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccProjectParameterText("/usr/lib/jvm/java-17", "read_only = true"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckParameterSpec(resName, "/usr/lib/jvm/java-17", "text display='normal' readOnly='true' validationMode='any'"),
+					resource.TestCheckResourceAttr(resName, "read_only", "true"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccProjectParameter_CoexistsWithParameterMaps checks the parameter maps of a project don't remove parameters managed by teamcity_project_parameter
+func TestAccProjectParameter_CoexistsWithParameterMaps(t *testing.T) {
+	resName := "teamcity_project_parameter.java_home"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckProjectParameterDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccProjectParameterWithMaps("config_value1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckParameterSpec(resName, "/usr/lib/jvm/java-11", "text display='normal' validationMode='any'"),
+					resource.TestCheckResourceAttr("teamcity_project.parameter_project", "env_params.%", "1"),
+					resource.TestCheckResourceAttr("teamcity_project.parameter_project", "env_params.PATH_EXT", ".sh"),
+				),
+			},
+			resource.TestStep{
+				Config:             testAccProjectParameterWithMaps("config_value1"),
+				ExpectNonEmptyPlan: false,
+			},
+			resource.TestStep{
+				Config: testAccProjectParameterWithMaps("config_value2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckParameterSpec(resName, "/usr/lib/jvm/java-11", "text display='normal' validationMode='any'"),
+					resource.TestCheckResourceAttr("teamcity_project.parameter_project", "config_params.variable1", "config_value2"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccProjectParameter_AlreadyExists checks a parameter defined outside the resource isn't taken over on create
+func TestAccProjectParameter_AlreadyExists(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckProjectParameterDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config:      testAccProjectParameterAlreadyExists,
+				ExpectError: regexp.MustCompile("parameter 'env.JAVA_HOME' already exists in project 'ParameterProject', import it instead"),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfigParameter_ValidationRegex(t *testing.T) {
+	resName := "teamcity_build_config_parameter.version"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccBuildConfigParameterRegex,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckParameterSpec(resName, "1.0.0",
+						"text display='normal' regexp='^\\d+\\.\\d+\\.\\d+$' validationMessage='Use semantic versioning' validationMode='regex'"),
+					resource.TestCheckResourceAttr(resName, "id", "ParameterProject_Build/system.version"),
+					resource.TestCheckResourceAttr(resName, "kind", "system"),
+					resource.TestCheckResourceAttr("teamcity_build_config.build", "sys_params.%", "0"),
+				),
+			},
+			resource.TestStep{
+				Config:             testAccBuildConfigParameterRegex,
+				ExpectNonEmptyPlan: false,
+			},
+			resource.TestStep{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestProjectParameter_SpecValidation(t *testing.T) {
+	p := testProviderWithHandler(t, http.NotFoundHandler())
+	r := p.ResourcesMap["teamcity_project_parameter"]
+
+	cases := map[string]struct {
+		args     map[string]interface{}
+		expected string
+	}{
+		"options with text": {
+			args:     map[string]interface{}{"option": []interface{}{map[string]interface{}{"value": "a"}}},
+			expected: "'option' cannot be set with parameter type 'text'",
+		},
+		"regex with checkbox": {
+			args:     map[string]interface{}{"type": "checkbox", "validation_regex": "^a$"},
+			expected: "'validation_regex' cannot be set with parameter type 'checkbox'",
+		},
+		"select without options": {
+			args:     map[string]interface{}{"type": "select"},
+			expected: "parameter type 'select' requires at least one 'option'",
+		},
+		"value with password": {
+			args:     map[string]interface{}{"type": "password", "value": "secret"},
+			expected: "'value' cannot be set with parameter type 'password', use 'password_value'",
+		},
+		"password_value with text": {
+			args:     map[string]interface{}{"password_value": "secret"},
+			expected: "'password_value' cannot be set with parameter type 'text'",
+		},
+		"password": {
+			args: map[string]interface{}{"type": "password", "password_value": "secret"},
+		},
+		"checkbox": {
+			args: map[string]interface{}{"type": "checkbox", "checked_value": "yes", "unchecked_value": "no"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			raw := map[string]interface{}{
+				"project_id": "Project",
+				"name":       "param",
+			}
+			for k, v := range c.args {
+				raw[k] = v
+			}
+
+			_, err := r.Diff(nil, terraform.NewResourceConfigRaw(raw), p.Meta())
+			if c.expected == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Fatalf("expected error containing %q, got %v", c.expected, err)
+			}
+		})
+	}
+}
+
+// testAccCheckParameterSpec checks the value and raw spec of a teamcity_project_parameter or teamcity_build_config_parameter
+func testAccCheckParameterSpec(n string, value string, spec string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		client := testAccProvider.Meta().(*teamcity.Client)
+		var actualValue, actualSpec string
+		var err error
+		if ownerID, ok := rs.Primary.Attributes["project_id"]; ok {
+			actualValue, actualSpec, err = client.ProjectParameterSpec(ownerID, strings.SplitN(rs.Primary.ID, "/", 2)[1])
+		} else {
+			actualValue, actualSpec, err = client.BuildConfigParameterSpec(rs.Primary.Attributes["build_config_id"], strings.SplitN(rs.Primary.ID, "/", 2)[1])
+		}
+		if err != nil {
+			return fmt.Errorf("Received an error retrieving parameter: %s", err)
+		}
+
+		if actualValue != value {
+			return fmt.Errorf("parameter value: got '%s', expected '%s'", actualValue, value)
+		}
+		if actualSpec != spec {
+			return fmt.Errorf("parameter spec: got '%s', expected '%s'", actualSpec, spec)
+		}
+		return nil
+	}
+}
+
+// testAccCheckParameterSpecByID checks the value and raw spec of a parameter of a project
+func testAccCheckParameterSpecByID(projectID string, name string, value string, spec string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		actualValue, actualSpec, err := testAccProvider.Meta().(*teamcity.Client).ProjectParameterSpec(projectID, name)
+		if err != nil {
+			return fmt.Errorf("Received an error retrieving parameter '%s': %s", name, err)
+		}
+		if actualValue != value || actualSpec != spec {
+			return fmt.Errorf("parameter '%s': got value '%s' and spec '%s', expected '%s' and '%s'", name, actualValue, actualSpec, value, spec)
+		}
+		return nil
+	}
+}
+
+// testAccCheckParameterDeleted checks a parameter of a project was deleted
+func testAccCheckParameterDeleted(projectID string, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		_, _, err := testAccProvider.Meta().(*teamcity.Client).ProjectParameterSpec(projectID, name)
+		if err == nil {
+			return fmt.Errorf("parameter '%s' still exists", name)
+		}
+		if !teamcity.IsNotFound(err) {
+			return fmt.Errorf("Received an error retrieving parameter '%s': %s", name, err)
+		}
+		return nil
+	}
+}
+
+// testAccCheckStoredParameterValue checks the value stored by the fake server for a parameter, since TeamCity never returns the values of password parameters.
+// ownerAttr is the attribute of resource n holding the ID of the project or build configuration. The check is skipped against a real server.
+func testAccCheckStoredParameterValue(n string, ownerAttr string, name string, value string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if testAccFakeServer == nil {
+			return nil
+		}
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		actual, ok := testAccFakeServer.storedParameterValue(rs.Primary.Attributes[ownerAttr], name)
+		if !ok {
+			return fmt.Errorf("parameter '%s' not found", name)
+		}
+		if actual != value {
+			return fmt.Errorf("value of parameter '%s': got '%s', expected '%s'", name, actual, value)
+		}
+		return nil
+	}
+}
+
+// testAccSetStoredParameterValue changes the value of a parameter outside Terraform. It does nothing against a real server.
+func testAccSetStoredParameterValue(ownerID string, name string, value string) {
+	if testAccFakeServer != nil {
+		testAccFakeServer.setStoredParameterValue(ownerID, name, value)
+	}
+}
+
+func testAccCheckProjectParameterDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*teamcity.Client)
+	for _, r := range s.RootModule().Resources {
+		if r.Type != "teamcity_project_parameter" {
+			continue
+		}
+
+		_, _, err := client.ProjectParameterSpec(r.Primary.Attributes["project_id"], strings.SplitN(r.Primary.ID, "/", 2)[1])
+		if err != nil {
+			if teamcity.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("Received an error retrieving the parameter: %s", err)
+		}
+
+		return fmt.Errorf("Parameter still exists")
+	}
+	return nil
+}
+
+const testAccProjectParameterSelect = `
+resource "teamcity_project" "parameter_project" {
+  name = "parameter_project"
+}
+
+resource "teamcity_project_parameter" "environment" {
+	project_id = teamcity_project.parameter_project.id
+	name = "deploy.environment"
+	value = "staging"
+	type = "select"
+	label = "Environment"
+	description = "Where to deploy, e.g. 'staging'"
+	display = "prompt"
+
+	option {
+		value = "staging"
+	}
+	option {
+		value = "production"
+		label = "Production [live]"
+	}
+}
+`
+
+func testAccProjectParameterPassword(rotation string) string {
+	return fmt.Sprintf(`
+resource "teamcity_project" "parameter_project" {
+  name = "parameter_project"
+}
+
+resource "teamcity_project_parameter" "token" {
+	project_id = teamcity_project.parameter_project.id
+	name = "DEPLOY_TOKEN"
+	kind = "environment"
+	type = "password"
+	display = "hidden"
+	password_value = "s3cr3t"
+	rotation_trigger = "%s"
+}
+`, rotation)
+}
+
+func testAccProjectParameterText(value string, extra string) string {
+	return fmt.Sprintf(`
+resource "teamcity_project" "parameter_project" {
+  name = "parameter_project"
+}
+
+resource "teamcity_project_parameter" "java_home" {
+	project_id = teamcity_project.parameter_project.id
+	name = "JAVA_HOME"
+	kind = "environment"
+	value = "%s"
+	%s
+}
+`, value, extra)
+}
+
+func testAccProjectParameterWithMaps(configValue string) string {
+	return fmt.Sprintf(`
+resource "teamcity_project" "parameter_project" {
+  name = "parameter_project"
+
+  config_params = {
+    variable1 = "%s"
+  }
+
+  env_params = {
+    PATH_EXT = ".sh"
+  }
+}
+
+resource "teamcity_project_parameter" "java_home" {
+	project_id = teamcity_project.parameter_project.id
+	name = "JAVA_HOME"
+	kind = "environment"
+	value = "/usr/lib/jvm/java-11"
+}
+`, configValue)
+}
+
+const testAccBuildConfigParameterRegex = `
+resource "teamcity_project" "parameter_project" {
+  name = "parameter_project"
+}
+
+resource "teamcity_build_config" "build" {
+	name = "build"
+	project_id = teamcity_project.parameter_project.id
+}
+
+resource "teamcity_build_config_parameter" "version" {
+	build_config_id = teamcity_build_config.build.id
+	name = "version"
+	kind = "system"
+	value = "1.0.0"
+	validation_regex = "^\\d+\\.\\d+\\.\\d+$"
+	validation_message = "Use semantic versioning"
+}
+`
+
+const testAccProjectParameterAlreadyExists = `
+resource "teamcity_project" "parameter_project" {
+  name = "parameter_project"
+
+  env_params = {
+    JAVA_HOME = "/usr/lib/jvm/java-8"
+  }
+}
+
+resource "teamcity_project_parameter" "java_home" {
+	project_id = teamcity_project.parameter_project.id
+	name = "JAVA_HOME"
+	kind = "environment"
+	value = "/usr/lib/jvm/java-11"
+}
+`
@@ -0,0 +1,273 @@
+package teamcity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"golang.org/x/crypto/ssh"
+)
+
+func resourceProjectSshKey() *schema.Resource {
+	return &schema.Resource{
+		Create: createContext(resourceProjectSshKeyCreate),
+		Read:   readContext(resourceProjectSshKeyRead),
+		Delete: deleteContext(resourceProjectSshKeyDelete),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+		CustomizeDiff: diffPrivateKeyFileHash,
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the project the key is uploaded to. The key is available to the project and its subprojects.",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "Name of the key, referenced by 'uploadedKey' VCS Root auth and by the SSH Agent build feature",
+			},
+			"private_key": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Sensitive:        true,
+				ExactlyOneOf:     []string{"private_key", "private_key_file"},
+				DiffSuppressFunc: suppressImportedSshKeyDiff,
+				Description:      "Content of the private key",
+			},
+			"private_key_file": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ExactlyOneOf:     []string{"private_key", "private_key_file"},
+				DiffSuppressFunc: suppressImportedSshKeyDiff,
+				Description:      "Path to a file holding the private key",
+			},
+			"private_key_file_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "SHA256 hash of the content of private_key_file, replacing the key when the file changes",
+			},
+			"fingerprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA256 fingerprint of the public key, as shown by ssh-keygen -l",
+			},
+		},
+	}
+}
+
+// sshKey is a key uploaded into a project, as returned by the projects/{id}/sshKeys endpoint. Private keys are never returned.
+type sshKey struct {
+	Name      string `json:"name"`
+	Encrypted bool   `json:"encrypted,omitempty"`
+	PublicKey string `json:"publicKey,omitempty"`
+}
+
+type sshKeys struct {
+	Items []*sshKey `json:"sshKey"`
+}
+
+func sshKeysPath(projectID string) string {
+	return fmt.Sprintf("%s/sshKeys", projectPath(projectID))
+}
+
+func (c *Client) uploadSshKey(projectID string, name string, privateKey string) error {
+	path := fmt.Sprintf("%s?fileName=%s", sshKeysPath(projectID), url.QueryEscape(name))
+	return c.doRequest("POST", path, formFile{field: "fileToUpload", fileName: name, content: privateKey}, nil, "SSH key")
+}
+
+// getSshKey returns the key with the given name, or nil if the project has none
+func (c *Client) getSshKey(projectID string, name string) (*sshKey, error) {
+	var out sshKeys
+	if err := c.doRequest("GET", sshKeysPath(projectID), nil, &out, "SSH keys"); err != nil {
+		return nil, err
+	}
+	for _, k := range out.Items {
+		if k.Name == name {
+			return k, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) deleteSshKey(projectID string, name string) error {
+	path := fmt.Sprintf("%s?fileName=%s", sshKeysPath(projectID), url.QueryEscape(name))
+	return c.doRequest("DELETE", path, nil, nil, "SSH key")
+}
+
+func resourceProjectSshKeyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	projectID := d.Get("project_id").(string)
+	name := d.Get("name").(string)
+
+	privateKey := d.Get("private_key").(string)
+	if v, ok := d.GetOk("private_key_file"); ok {
+		dt, err := ioutil.ReadFile(v.(string))
+		if err != nil {
+			return fmt.Errorf("error reading private_key_file: %s", err)
+		}
+		privateKey = string(dt)
+		if err := d.Set("private_key_file_hash", sha256Hex(dt)); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[INFO] Uploading SSH key '%s' to project '%s'", name, projectID)
+	if err := client.uploadSshKey(projectID, name, privateKey); err != nil {
+		return err
+	}
+	d.SetId(projectSshKeyID(projectID, name))
+
+	return resourceProjectSshKeyRead(ctx, d, meta)
+}
+
+func resourceProjectSshKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	projectID, name, err := parseProjectSshKeyID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	dt, err := client.getSshKey(projectID, name)
+	if err != nil {
+		return handleNotFound(d, client, projectPath(projectID), err)
+	}
+	if dt == nil {
+		if d.IsNewResource() {
+			return fmt.Errorf("SSH key '%s' not found in project '%s' after upload", name, projectID)
+		}
+		log.Printf("[WARN] SSH key '%s' not found in project '%s', removing from state", name, projectID)
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("project_id", projectID); err != nil {
+		return err
+	}
+	if err := d.Set("name", dt.Name); err != nil {
+		return err
+	}
+	if err := d.Set("fingerprint", sshKeyFingerprint(dt.PublicKey)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func resourceProjectSshKeyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	projectID, name, err := parseProjectSshKeyID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	err = client.deleteSshKey(projectID, name)
+	if isNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// diffPrivateKeyFileHash replaces the key when the content of private_key_file changes, since only its path is in the configuration.
+// A file that can't be read yet, e.g. one written by another resource during apply, is left to Create.
+func diffPrivateKeyFileHash(diff *schema.ResourceDiff, meta interface{}) error {
+	v, ok := diff.GetOk("private_key_file")
+	if !ok || !diff.NewValueKnown("private_key_file") {
+		return nil
+	}
+	dt, err := ioutil.ReadFile(v.(string))
+	if err != nil {
+		log.Printf("[DEBUG] Not checking private_key_file for changes: %s", err)
+		return nil
+	}
+	old := diff.Get("private_key_file_hash").(string)
+	// Imported keys have no hash, the fingerprint tells whether the file holds the same key
+	if old == "" && diff.Id() != "" && sameSshKey(string(dt), diff.Get("fingerprint").(string)) {
+		return nil
+	}
+	if hash := sha256Hex(dt); hash != old {
+		return diff.SetNew("private_key_file_hash", hash)
+	}
+	return nil
+}
+
+// suppressImportedSshKeyDiff keeps an imported key, whose private key TeamCity never returns,
+// when the configured private key has the fingerprint of the uploaded one
+func suppressImportedSshKeyDiff(k, old, new string, d *schema.ResourceData) bool {
+	if old != "" || new == "" || d.Id() == "" {
+		return false
+	}
+	privateKey := new
+	if k == "private_key_file" {
+		dt, err := ioutil.ReadFile(new)
+		if err != nil {
+			return false
+		}
+		privateKey = string(dt)
+	}
+	return sameSshKey(privateKey, d.Get("fingerprint").(string))
+}
+
+// sameSshKey reports whether the private key has the given public key fingerprint.
+// Keys that can't be parsed, e.g. encrypted ones, never match.
+func sameSshKey(privateKey string, fingerprint string) bool {
+	if fingerprint == "" {
+		return false
+	}
+	signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+	if err != nil {
+		return false
+	}
+	return ssh.FingerprintSHA256(signer.PublicKey()) == fingerprint
+}
+
+func sha256Hex(dt []byte) string {
+	sum := sha256.Sum256(dt)
+	return hex.EncodeToString(sum[:])
+}
+
+func projectSshKeyID(projectID string, name string) string {
+	return fmt.Sprintf("%s/%s", projectID, name)
+}
+
+func parseProjectSshKeyID(id string) (projectID string, name string, err error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid SSH key ID '%s', expected '<project_id>/<name>'", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+// sshKeyFingerprint returns the SHA256 fingerprint of a public key in the authorized_keys format, or an empty string if it can't be parsed
+func sshKeyFingerprint(publicKey string) string {
+	fields := strings.Fields(publicKey)
+	if len(fields) < 2 {
+		return ""
+	}
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(blob)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
@@ -128,6 +128,51 @@ func TestAccTeamcityProject_Update(t *testing.T) {
 	})
 }
 
+func TestAccTeamcityProject_PasswordParams(t *testing.T) {
+	resName := "teamcity_project.testproj"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckTeamcityProjectDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccTeamcityProjectPasswordParams("db_password_1", "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "password_params.%", "2"),
+					resource.TestCheckResourceAttr(resName, "config_params.%", "1"),
+					testAccCheckParameterSpecByID("TestProject", "db.password", "", "password"),
+					testAccCheckParameterSpecByID("TestProject", "env.DEPLOY_TOKEN", "", "password"),
+					testAccCheckStoredParameterValue(resName, "id", "db.password", "db_password_1"),
+					testAccCheckStoredParameterValue(resName, "id", "env.DEPLOY_TOKEN", "token"),
+				),
+			},
+			resource.TestStep{
+				PreConfig: func() { testAccSetStoredParameterValue("TestProject", "env.DEPLOY_TOKEN", "changed in the UI") },
+				Config:    testAccTeamcityProjectPasswordParams("db_password_2", "1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStoredParameterValue(resName, "id", "db.password", "db_password_2"),
+					testAccCheckStoredParameterValue(resName, "id", "env.DEPLOY_TOKEN", "changed in the UI"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccTeamcityProjectPasswordParams("db_password_2", "2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStoredParameterValue(resName, "id", "db.password", "db_password_2"),
+					testAccCheckStoredParameterValue(resName, "id", "env.DEPLOY_TOKEN", "token"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccTeamcityProjectFullUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "password_params.%", "0"),
+					testAccCheckParameterDeleted("TestProject", "db.password"),
+					testAccCheckParameterDeleted("TestProject", "env.DEPLOY_TOKEN"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckProjectParameter(dt *api.Project, paramType string, paramName string, paramValue string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		for _, i := range dt.Parameters.Items {
@@ -148,7 +193,7 @@ func testAccCheckProjectParameter(dt *api.Project, paramType string, paramName s
 
 func testAccCheckTeamcityProjectExists(n string, project *api.Project) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccClient()
 		return teamcityProjectExistsHelper(n, s, client, project)
 	}
 }
@@ -175,7 +220,7 @@ func teamcityProjectExistsHelper(n string, s *terraform.State, client *api.Clien
 }
 
 func testAccCheckTeamcityProjectDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*api.Client)
+	client := testAccClient()
 	return teamcityProjectDestroyHelper(s, client)
 }
 
@@ -238,6 +283,25 @@ resource "teamcity_project" "testproj" {
 }
 `
 
+func testAccTeamcityProjectPasswordParams(dbPassword string, rotation string) string {
+	return fmt.Sprintf(`
+resource "teamcity_project" "testproj" {
+	name = "test_project"
+	description = "Test Project Updated"
+
+	config_params = {
+		param1 = "config_value1"
+	}
+
+	password_params = {
+		"db.password" = "%s"
+		"env.DEPLOY_TOKEN" = "token"
+	}
+	password_rotation_trigger = "%s"
+}
+`, dbPassword, rotation)
+}
+
 const testAccTeamcityProjectFullUpdated = `
 resource "teamcity_project" "testproj" {
 	name = "test_project"
@@ -1,6 +1,7 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -9,12 +10,16 @@ import (
 
 func resourceSnapshotDependency() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceSnapshotDependencyCreate,
-		Read:   resourceSnapshotDependencyRead,
-		Delete: resourceSnapshotDependencyDelete,
+		Create: createContext(resourceSnapshotDependencyCreate),
+		Read:   readContext(resourceSnapshotDependencyRead),
+		Delete: deleteContext(resourceSnapshotDependencyDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"build_config_id": {
@@ -31,22 +36,21 @@ func resourceSnapshotDependency() *schema.Resource {
 	}
 }
 
-func resourceSnapshotDependencyCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceSnapshotDependencyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	var buildConfigID string
 
 	if v, ok := d.GetOk("build_config_id"); ok {
 		buildConfigID = v.(string)
 	}
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
+	if _, err := getBuildConfiguration(client, buildConfigID); err != nil {
 		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
 	}
 
-	depService := client.DependencyService(buildConfigID)
 	dep := api.NewSnapshotDependency(d.Get("source_build_config_id").(string))
 
-	out, err := depService.AddSnapshotDependency(dep)
+	out, err := addSnapshotDependency(client, buildConfigID, dep)
 
 	if err != nil {
 		return err
@@ -54,15 +58,13 @@ func resourceSnapshotDependencyCreate(d *schema.ResourceData, meta interface{})
 
 	d.SetId(out.ID)
 
-	return resourceSnapshotDependencyRead(d, meta)
+	return resourceSnapshotDependencyRead(ctx, d, meta)
 }
 
-func resourceSnapshotDependencyRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).DependencyService(d.Get("build_config_id").(string))
-
-	dt, err := getSnapshotDependency(client, d.Id())
+func resourceSnapshotDependencyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	dt, err := getSnapshotDependency(meta.(*Client), d.Get("build_config_id").(string), d.Id())
 	if err != nil {
-		return err
+		return handleNotFound(d, meta.(*Client), buildTypeItemPath(d.Get("build_config_id").(string), "snapshot-dependencies", d.Id()), err)
 	}
 
 	if err := d.Set("build_config_id", dt.BuildTypeID); err != nil {
@@ -72,19 +74,28 @@ func resourceSnapshotDependencyRead(d *schema.ResourceData, meta interface{}) er
 	return d.Set("source_build_config_id", dt.SourceBuildType.ID)
 }
 
-func resourceSnapshotDependencyDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceSnapshotDependencyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	dep := client.DependencyService(d.Get("build_config_id").(string))
 
 	return dep.DeleteSnapshot(d.Id())
 }
 
-func getSnapshotDependency(c *api.DependencyService, id string) (*api.SnapshotDependency, error) {
-
-	dt, err := c.GetSnapshotByID(id)
-	if err != nil {
+// addSnapshotDependency and getSnapshotDependency replace their go-teamcity counterparts, which are sent with http.DefaultClient and would skip the provider transport settings
+func addSnapshotDependency(c *Client, buildTypeID string, dep *api.SnapshotDependency) (*api.SnapshotDependency, error) {
+	var out api.SnapshotDependency
+	if err := c.doRequest("POST", buildTypePath(buildTypeID)+"/snapshot-dependencies", dep, &out, "snapshot dependency"); err != nil {
 		return nil, err
 	}
+	out.BuildTypeID = buildTypeID
+	return &out, nil
+}
 
-	return dt, nil
+func getSnapshotDependency(c *Client, buildTypeID string, id string) (*api.SnapshotDependency, error) {
+	var out api.SnapshotDependency
+	if err := c.doRequest("GET", buildTypeItemPath(buildTypeID, "snapshot-dependencies", id), nil, &out, "snapshot dependency"); err != nil {
+		return nil, err
+	}
+	out.BuildTypeID = buildTypeID
+	return &out, nil
 }
@@ -91,7 +91,7 @@ func testAccCheckSnapshotSourceBuildType(n string, sd *api.SnapshotDependency) r
 
 func testAccCheckTeamcitySnapshotDependencyDestroy(bt *string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccClient()
 		return snapshotDependencyDestroyHelper(s, bt, client)
 	}
 }
@@ -119,7 +119,7 @@ func snapshotDependencyDestroyHelper(s *terraform.State, bt *string, client *api
 
 func testAccCheckTeamcitySnapshotDependencyExists(n string, bt *string, snap *api.SnapshotDependency) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccClient()
 		return teamcitySnapshotDependencyExistsHelper(n, bt, s, client, snap)
 	}
 }
@@ -2,8 +2,11 @@ package teamcity
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
@@ -14,13 +17,19 @@ import (
 
 func resourceVcsRootGit() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVcsRootGitCreate,
-		Read:   resourceVcsRootGitRead,
-		Update: resourceVcsRootGitUpdate,
-		Delete: resourceVcsRootGitDelete,
+		Create: createContext(resourceVcsRootGitCreate),
+		Read:   readContext(resourceVcsRootGitRead),
+		Update: updateContext(resourceVcsRootGitUpdate),
+		Delete: deleteContext(resourceVcsRootGitDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+		CustomizeDiff: validateGitVcsRootAuth,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -94,7 +103,7 @@ func resourceVcsRootGit() *schema.Resource {
 					Schema: map[string]*schema.Schema{
 						"type": {
 							Type:         schema.TypeString,
-							ValidateFunc: validation.StringInSlice([]string{"userpass", "ssh", "anonymous"}, true),
+							ValidateFunc: validation.StringInSlice([]string{"userpass", "ssh", "anonymous", "access_token", "token"}, true),
 							Required:     true,
 						},
 						"username": {
@@ -120,6 +129,17 @@ func resourceVcsRootGit() *schema.Resource {
 							Computed:    true,
 							Description: "Password if using 'userpass' auth. Private key passphrase if using 'uploadedKey' or 'customKey'. Required if not anonymous auth.",
 						},
+						"access_token": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Personal access token if using 'access_token' auth.",
+						},
+						"token_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "ID of a refreshable token issued by a project connection (GitHub App, GitLab, Bitbucket Cloud...) if using 'token' auth.",
+						},
 					},
 				},
 				Set: gitVcsAuthHash,
@@ -150,13 +170,84 @@ func resourceVcsRootGit() *schema.Resource {
 							Optional: true,
 							Default:  true,
 						},
+						"checkout_policy": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"auto", "use_mirrors", "no_mirrors", "shallow_clone"}, false),
+							Description:  "How the repository is cloned on the agent. Allowed values: 'auto', 'use_mirrors', 'no_mirrors', 'shallow_clone'",
+						},
+						"clone_depth": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "Number of commits fetched when cloning on the agent. 0 fetches the whole history.",
+						},
+						"lfs": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "If true, Git LFS objects are fetched on the agent",
+						},
+						"sparse_checkout": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "If true, only the paths included by the checkout rules are checked out on the agent",
+						},
+						"submodule_recursion": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: "If true, submodules of submodules are checked out as well",
+						},
 					},
 				},
 			},
+			"convert_crlf": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, line endings of text files are converted to CRLF on checkout",
+			},
+			"server_git_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The path to the git executable used on the server. If blank, the git bundled with TeamCity is used.",
+			},
+			"use_trusted_ssl_certificates": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "If true, HTTPS connections trust the certificates uploaded to the project in addition to the default ones",
+			},
+			"ssl_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "If false, SSL certificates of the repository server are not verified",
+			},
+			"ignore_known_hosts": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "If true, the known hosts database is not checked when connecting with SSH",
+			},
 		},
 	}
 }
 
+var expandCheckoutPolicyMap = map[string]string{
+	"auto":          "AUTO",
+	"use_mirrors":   "USE_MIRRORS",
+	"no_mirrors":    "NO_MIRRORS",
+	"shallow_clone": "SHALLOW_CLONE",
+}
+
+var flattenCheckoutPolicyMap = reverseMap(expandCheckoutPolicyMap)
+
+// gitAuthMethodAccessToken authenticates with a refreshable token issued by a project connection
+const gitAuthMethodAccessToken api.GitAuthMethod = "ACCESS_TOKEN"
+
 var expandUsernameStyleMap = map[string]string{
 	"userid":       string(api.GitVcsUsernameStyleUserID),
 	"author_email": string(api.GitVcsUsernameStyleAuthorEmail),
@@ -182,141 +273,90 @@ var expandCleanFilesPolicyMap = map[string]string{
 
 var flattenCleanFilesPolicyMap = reverseMap(expandCleanFilesPolicyMap)
 
-func resourceVcsRootGitCreate(d *schema.ResourceData, meta interface{}) error {
-	d.MarkNewResource()
-	return resourceVcsRootGitUpdate(d, meta)
-}
-
-func resourceVcsRootGitUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
-	projectID := d.Get("project_id").(string)
-	var gitVcs *api.GitVcsRoot
-	var name string
-	var modificationCheckInterval int
-
-	vcsOpts, err := expandGitVcsRootOptions(d)
+func resourceVcsRootGitCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 
+	dt, err := expandGitVcsRoot(d)
 	if err != nil {
 		return err
 	}
 
-	if v, ok := d.GetOk("name"); ok {
-		name = v.(string)
-	}
-	if v, ok := d.GetOk("username_style"); ok {
-		vcsOpts.UsernameStyle = api.GitVcsUsernameStyle(expandUsernameStyleMap[v.(string)])
-	}
-
-	if v, ok := d.GetOk("enable_branch_spec_tags"); ok {
-		vcsOpts.EnableTagsInBranchSpec = v.(bool)
+	log.Printf("[INFO] detected new VCS Root resource, creating.")
+	created, err := client.createVcsRoot(dt)
+	if err != nil {
+		return err
 	}
+	d.SetId(created.ID)
 
-	if v, ok := d.GetOk("modification_check_interval"); ok {
-		modificationCheckInterval = v.(int)
-	}
+	return resourceVcsRootGitRead(ctx, d, meta)
+}
 
-	if d.IsNewResource() {
-		log.Printf("[INFO] detected new VCS Root resource, creating.")
-		if gitVcs, err = api.NewGitVcsRoot(projectID, name, vcsOpts); err != nil {
-			return err
-		}
-		if modificationCheckInterval > 0 {
-			gitVcs.SetModificationCheckInterval(int32(modificationCheckInterval))
-		}
-		created, err := client.VcsRoots.Create(projectID, gitVcs)
-		if err != nil {
-			return err
-		}
-		d.SetId(created.ID)
+func resourceVcsRootGitUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 
-		return resourceVcsRootGitRead(d, meta)
-	}
-	log.Printf("[INFO] Updating VCS Root resource.")
-	vcs, err := client.VcsRoots.GetByID(d.Id())
+	dt, err := expandGitVcsRoot(d)
 	if err != nil {
 		return err
 	}
 
-	gitVcs = vcs.(*api.GitVcsRoot)
-	if d.HasChange("name") {
-		gitVcs.SetName(name)
-	}
-	if d.HasChange("project_id") {
-		gitVcs.SetProjectID(projectID)
-	}
-	if d.HasChange("modification_check_interval") {
-		gitVcs.SetModificationCheckInterval(int32(modificationCheckInterval))
-	}
-	if d.HasChange("auth") {
-		gitVcs.Options.AuthMethod = vcsOpts.AuthMethod
-		gitVcs.Options.Password = vcsOpts.Password
-		gitVcs.Options.PrivateKeySource = vcsOpts.PrivateKeySource
-		gitVcs.Options.Username = vcsOpts.Username
+	log.Printf("[INFO] Updating VCS Root resource.")
+	if err := client.updateVcsRoot(d, dt); err != nil {
+		return err
 	}
 
-	if d.HasChange("agent") {
-		gitVcs.Options.AgentSettings = vcsOpts.AgentSettings
-	}
+	return resourceVcsRootGitRead(ctx, d, meta)
+}
 
-	if d.HasChange("username_style") {
-		gitVcs.Options.UsernameStyle = vcsOpts.UsernameStyle
+// expandGitVcsRoot builds the VCS Root properties with go-teamcity, then adds the ones it does not model
+func expandGitVcsRoot(d *schema.ResourceData) (*vcsRoot, error) {
+	vcsOpts, err := expandGitVcsRootOptions(d)
+	if err != nil {
+		return nil, err
 	}
 
-	if d.HasChange("enable_branch_spec_tags") {
-		gitVcs.Options.EnableTagsInBranchSpec = vcsOpts.EnableTagsInBranchSpec
+	if v, ok := d.GetOk("username_style"); ok {
+		vcsOpts.UsernameStyle = api.GitVcsUsernameStyle(expandUsernameStyleMap[v.(string)])
 	}
 
-	if d.HasChange("push_url") {
-		gitVcs.Options.PushURL = vcsOpts.PushURL
-	}
-	if d.HasChange("fetch_url") {
-		gitVcs.Options.FetchURL = vcsOpts.FetchURL
-	}
-	if d.HasChange("branches") {
-		gitVcs.Options.BranchSpec = vcsOpts.BranchSpec
-	}
-	if d.HasChange("default_branch") {
-		gitVcs.Options.DefaultBranch = vcsOpts.DefaultBranch
-	}
-	if d.HasChange("submodule_checkout") {
-		gitVcs.Options.SubModuleCheckout = vcsOpts.SubModuleCheckout
+	if v, ok := d.GetOk("enable_branch_spec_tags"); ok {
+		vcsOpts.EnableTagsInBranchSpec = v.(bool)
 	}
 
-	_, err = client.VcsRoots.Update(gitVcs)
+	gitVcs, err := api.NewGitVcsRoot(d.Get("project_id").(string), d.Get("name").(string), vcsOpts)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return resourceVcsRootGitRead(d, meta)
-}
+	props := gitVcs.Properties()
+	expandGitVcsRootTokenAuth(d, props)
+	expandGitVcsAgentProperties(d, props)
 
-func resourceVcsRootGitRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
-	vcsID := d.Id()
+	props.AddOrReplaceValue("serverSideAutoCrlf", strconv.FormatBool(d.Get("convert_crlf").(bool)))
+	setProperty(props, "serverGitPath", d.Get("server_git_path").(string))
+	props.AddOrReplaceValue("useTrustedCertificates", strconv.FormatBool(d.Get("use_trusted_ssl_certificates").(bool)))
+	props.AddOrReplaceValue("sslVerify", strconv.FormatBool(d.Get("ssl_verify").(bool)))
+	props.AddOrReplaceValue("ignoreKnownHosts", strconv.FormatBool(d.Get("ignore_known_hosts").(bool)))
 
-	vcs, err := client.VcsRoots.GetByID(vcsID)
-	if err != nil {
-		return err
-	}
+	return newVcsRoot(d, api.VcsNames.Git, props), nil
+}
 
-	dt, ok := vcs.(*api.GitVcsRoot)
-	if !ok {
-		return fmt.Errorf("VCS with ID = %s has a type mismatch, not a Git VCS. Actual type: %s", vcsID, vcs.VcsName())
-	}
+func resourceVcsRootGitRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 
-	if err := d.Set("project_id", dt.Project.ID); err != nil {
+	raw, err := readVcsRoot(d, client, api.VcsNames.Git)
+	if raw == nil {
 		return err
 	}
+	props := raw.Properties.Map()
 
-	if err := d.Set("name", dt.Name()); err != nil {
+	// Let go-teamcity parse the properties it models
+	var dt api.GitVcsRoot
+	body, err := json.Marshal(raw)
+	if err != nil {
 		return err
 	}
-
-	if dt.ModificationCheckInterval() != nil {
-		v := *(dt.ModificationCheckInterval())
-		if err := d.Set("modification_check_interval", int(v)); err != nil {
-			return err
-		}
+	if err := dt.UnmarshalJSON(body); err != nil {
+		return err
 	}
 
 	if err := d.Set("fetch_url", dt.Options.FetchURL); err != nil {
@@ -337,10 +377,12 @@ func resourceVcsRootGitRead(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
-	if auth, err := flattenGitVcsRootAuth(d, dt.Options); err != nil {
-		if err := d.Set("auth", auth); err != nil {
-			return err
-		}
+	auth, err := flattenGitVcsRootAuth(d, dt.Options, props)
+	if err != nil {
+		return err
+	}
+	if err := d.Set("auth", auth); err != nil {
+		return err
 	}
 
 	if err := d.Set("submodule_checkout", dt.Options.SubModuleCheckout); err != nil {
@@ -355,17 +397,35 @@ func resourceVcsRootGitRead(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
-	if agent, err := flattenGitAgentSettings(d, dt.Options.AgentSettings); err != nil {
-		if err := d.Set("agent", agent); err != nil {
-			return err
-		}
+	agent, err := flattenGitAgentSettings(d, dt.Options.AgentSettings, props)
+	if err != nil {
+		return err
+	}
+	if err := d.Set("agent", agent); err != nil {
+		return err
+	}
+
+	if err := d.Set("convert_crlf", boolProperty(props, "serverSideAutoCrlf", false)); err != nil {
+		return err
+	}
+	if err := d.Set("server_git_path", props["serverGitPath"]); err != nil {
+		return err
+	}
+	if err := d.Set("use_trusted_ssl_certificates", boolProperty(props, "useTrustedCertificates", true)); err != nil {
+		return err
+	}
+	if err := d.Set("ssl_verify", boolProperty(props, "sslVerify", true)); err != nil {
+		return err
+	}
+	if err := d.Set("ignore_known_hosts", boolProperty(props, "ignoreKnownHosts", true)); err != nil {
+		return err
 	}
 
 	return nil
 }
 
-func resourceVcsRootGitDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceVcsRootGitDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
 	log.Print(fmt.Sprintf("[DEBUG]: resourceVcsRootGitDelete - Destroying vcs root %v", d.Id()))
 	err := client.VcsRoots.Delete(d.Id())
 	log.Print(fmt.Sprintf("[INFO]: resourceVcsRootGitDelete - Destroyed vcs root %v", d.Id()))
@@ -465,7 +525,26 @@ func expandGitVcsAgentSettings(d *schema.ResourceData) (*api.GitAgentSettings, e
 	}, nil
 }
 
-func flattenGitAgentSettings(d *schema.ResourceData, dt *api.GitAgentSettings) ([]map[string]interface{}, error) {
+// expandGitVcsAgentProperties sets the agent settings go-teamcity does not model
+func expandGitVcsAgentProperties(d *schema.ResourceData, props *api.Properties) {
+	v, ok := d.GetOk("agent")
+	if !ok {
+		return
+	}
+	agent := v.(*schema.Set).List()[0].(map[string]interface{})
+
+	if v := agent["checkout_policy"].(string); v != "" {
+		props.AddOrReplaceValue("agentCheckoutPolicy", expandCheckoutPolicyMap[v])
+	}
+	if v := agent["clone_depth"].(int); v > 0 {
+		props.AddOrReplaceValue("cloneDepth", strconv.Itoa(v))
+	}
+	props.AddOrReplaceValue("useLfs", strconv.FormatBool(agent["lfs"].(bool)))
+	props.AddOrReplaceValue("useSparseCheckout", strconv.FormatBool(agent["sparse_checkout"].(bool)))
+	props.AddOrReplaceValue("recursiveSubmodules", strconv.FormatBool(agent["submodule_recursion"].(bool)))
+}
+
+func flattenGitAgentSettings(d *schema.ResourceData, dt *api.GitAgentSettings, props map[string]string) ([]map[string]interface{}, error) {
 	if dt == nil {
 		return nil, nil
 	}
@@ -484,11 +563,46 @@ func flattenGitAgentSettings(d *schema.ResourceData, dt *api.GitAgentSettings) (
 
 	m["use_mirrors"] = dt.UseMirrors
 
+	if v, ok := flattenCheckoutPolicyMap[props["agentCheckoutPolicy"]]; ok {
+		m["checkout_policy"] = v
+	}
+	if v, err := strconv.Atoi(props["cloneDepth"]); err == nil {
+		m["clone_depth"] = v
+	}
+	m["lfs"] = boolProperty(props, "useLfs", false)
+	m["sparse_checkout"] = boolProperty(props, "useSparseCheckout", false)
+	m["submodule_recursion"] = boolProperty(props, "recursiveSubmodules", true)
+
+	// Without an agent block, TeamCity gets the default agent settings, which are not saved to state
+	if _, ok := d.GetOk("agent"); !ok && isDefaultGitAgentSettings(m) {
+		return nil, nil
+	}
+
 	optsToSave = append(optsToSave, m)
 	return optsToSave, nil
 }
 
-func flattenGitVcsRootAuth(d *schema.ResourceData, dt *api.GitVcsRootOptions) ([]map[string]interface{}, error) {
+func isDefaultGitAgentSettings(m map[string]interface{}) bool {
+	defaults := map[string]interface{}{
+		"clean_policy":        flattenCleanPolicyMap[string(api.CleanPolicyBranchChange)],
+		"clean_files_policy":  flattenCleanFilesPolicyMap[string(api.CleanFilesPolicyAllUntracked)],
+		"use_mirrors":         true,
+		"lfs":                 false,
+		"sparse_checkout":     false,
+		"submodule_recursion": true,
+	}
+	if len(m) != len(defaults) {
+		return false
+	}
+	for k, v := range defaults {
+		if m[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func flattenGitVcsRootAuth(d *schema.ResourceData, dt *api.GitVcsRootOptions, props map[string]string) ([]map[string]interface{}, error) {
 	var optsToSave []map[string]interface{}
 	m := make(map[string]interface{})
 	authType, sshType := readAuthTypeFromAuthMethod(dt)
@@ -497,6 +611,21 @@ func flattenGitVcsRootAuth(d *schema.ResourceData, dt *api.GitVcsRootOptions) ([
 		return nil, fmt.Errorf("invalid auth method returned from api: '%s'", dt.AuthMethod)
 	}
 
+	var authSet map[string]interface{}
+	if auth, ok := d.GetOk("auth"); ok {
+		authSet = auth.(*schema.Set).List()[0].(map[string]interface{})
+	}
+
+	// Anonymous auth is the default when no auth block is configured
+	if authType == "anonymous" && authSet == nil {
+		return nil, nil
+	}
+
+	// TeamCity stores access tokens as passwords, so the configured type is kept
+	if authType == "userpass" && authSet != nil && authSet["type"].(string) == "access_token" {
+		authType = "access_token"
+	}
+
 	m["type"] = authType
 	if authType == "ssh" {
 		m["ssh_type"] = sshType
@@ -504,23 +633,112 @@ func flattenGitVcsRootAuth(d *schema.ResourceData, dt *api.GitVcsRootOptions) ([
 			m["key_spec"] = dt.PrivateKeySource
 		}
 	}
+	if authType == "token" {
+		m["token_id"] = props["tokenId"]
+	}
 
 	if dt.Username != "" {
 		m["username"] = dt.Username
 	}
 
-	//Set back password if contained in state
-	if auth, ok := d.GetOk("auth"); ok {
-		authSet := auth.(*schema.Set).List()[0].(map[string]interface{})
+	//Set back password and access token if contained in state
+	if authSet != nil {
 		if pwd, ok := authSet["password"]; ok {
 			m["password"] = pwd.(string)
 		}
+		if token, ok := authSet["access_token"]; ok {
+			m["access_token"] = token.(string)
+		}
 	}
 
 	optsToSave = append(optsToSave, m)
 	return optsToSave, nil
 }
 
+// expandGitVcsRootTokenAuth sets the properties of the token auth types, which go-teamcity does not model
+func expandGitVcsRootTokenAuth(d *schema.ResourceData, props *api.Properties) {
+	a, ok := d.GetOk("auth")
+	if !ok {
+		return
+	}
+	auth := a.(*schema.Set).List()[0].(map[string]interface{})
+
+	switch auth["type"].(string) {
+	case "access_token":
+		props.AddOrReplaceValue("secure:password", auth["access_token"].(string))
+	case "token":
+		setProperty(props, "username", auth["username"].(string))
+		props.AddOrReplaceValue("tokenId", auth["token_id"].(string))
+	}
+}
+
+// gitAuthFields lists the auth block fields each auth type requires, and the ones it does not use
+var gitAuthFields = map[string]struct{ required, unused []string }{
+	"anonymous":    {unused: []string{"username", "password", "ssh_type", "key_spec", "access_token", "token_id"}},
+	"userpass":     {required: []string{"username"}, unused: []string{"ssh_type", "key_spec", "access_token", "token_id"}},
+	"ssh":          {required: []string{"ssh_type"}, unused: []string{"access_token", "token_id"}},
+	"access_token": {required: []string{"username", "access_token"}, unused: []string{"password", "ssh_type", "key_spec", "token_id"}},
+	"token":        {required: []string{"token_id"}, unused: []string{"password", "ssh_type", "key_spec", "access_token"}},
+}
+
+// newGitAuthFields are the auth types and fields added along with the checks below. Configurations using only older ones
+// were accepted before, so their problems are logged as warnings rather than failing the plan.
+var newGitAuthFields = map[string]bool{"access_token": true, "token": true, "token_id": true}
+
+// refreshableTokenServerVersion is the first TeamCity release supporting refreshable tokens issued by project connections
+var refreshableTokenServerVersion = mustParseServerVersion("2022.10")
+
+// validateGitVcsRootAuth checks at plan time that the auth block sets the fields its type requires
+func validateGitVcsRootAuth(diff *schema.ResourceDiff, meta interface{}) error {
+	if !diff.NewValueKnown("auth") {
+		return nil
+	}
+	a, ok := diff.GetOk("auth")
+	if !ok {
+		return nil
+	}
+	auth := a.(*schema.Set).List()[0].(map[string]interface{})
+	authType := strings.ToLower(auth["type"].(string))
+
+	check := func(field string, err error) error {
+		if newGitAuthFields[authType] || newGitAuthFields[field] {
+			return err
+		}
+		log.Printf("[WARN] VCS root '%s': %s", diff.Get("name").(string), err)
+		return nil
+	}
+
+	fields := gitAuthFields[authType]
+	for _, f := range fields.required {
+		if auth[f].(string) == "" {
+			if err := check(f, fmt.Errorf("auth type '%s' requires '%s' to be set", authType, f)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range fields.unused {
+		if auth[f].(string) != "" {
+			if err := check(f, fmt.Errorf("'%s' cannot be set with auth type '%s'", f, authType)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if authType == "ssh" {
+		sshType := auth["ssh_type"].(string)
+		if (sshType == "uploadedKey" || sshType == "customKey") && auth["key_spec"].(string) == "" {
+			if err := check("key_spec", fmt.Errorf("ssh_type '%s' requires 'key_spec' to be set", sshType)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if authType == "token" {
+		return checkServerVersion(meta, refreshableTokenServerVersion, "auth type 'token'")
+	}
+	return nil
+}
+
 func getGitAuthType(d *schema.ResourceData) (api.GitAuthMethod, error) {
 
 	// If no auth specified, assume "anonymous"
@@ -538,6 +756,10 @@ func getGitAuthType(d *schema.ResourceData) (api.GitAuthMethod, error) {
 		return api.GitAuthMethodPassword, nil
 	case "anonymous":
 		return api.GitAuthMethodAnonymous, nil
+	case "access_token":
+		return api.GitAuthMethodPassword, nil
+	case "token":
+		return gitAuthMethodAccessToken, nil
 	case "ssh":
 		sshType := auth["ssh_type"].(string)
 		switch sshType {
@@ -565,6 +787,8 @@ func readAuthTypeFromAuthMethod(vcsOpt *api.GitVcsRootOptions) (string, string)
 		return "ssh", "defaultKey"
 	case api.GitAuthSSHUploadedKey:
 		return "ssh", "uploadedKey"
+	case gitAuthMethodAccessToken:
+		return "token", ""
 	}
 
 	return "", ""
@@ -2,6 +2,7 @@ package teamcity_test
 
 import (
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 	"testing"
@@ -179,16 +180,84 @@ func TestAccVcsRootGit_AgentSettings(t *testing.T) {
 					testAccCheckVcsRootGitExists(resourceName, &vcs),
 					testAccCheckVcsRootGitAgentSettings(&vcs, expected),
 					resource.TestCheckResourceAttr(resourceName, "agent.#", "1"),
-					resource.TestCheckResourceAttr(resourceName, "agent.2618137949.git_path", "/usr/bin/git"),
-					resource.TestCheckResourceAttr(resourceName, "agent.2618137949.clean_policy", "branch_change"),
-					resource.TestCheckResourceAttr(resourceName, "agent.2618137949.clean_files_policy", "ignored_only"),
-					resource.TestCheckResourceAttr(resourceName, "agent.2618137949.use_mirrors", "true"),
+					resource.TestCheckResourceAttr(resourceName, "agent.1669875071.git_path", "/usr/bin/git"),
+					resource.TestCheckResourceAttr(resourceName, "agent.1669875071.clean_policy", "branch_change"),
+					resource.TestCheckResourceAttr(resourceName, "agent.1669875071.clean_files_policy", "ignored_only"),
+					resource.TestCheckResourceAttr(resourceName, "agent.1669875071.use_mirrors", "true"),
 				),
 			},
 		},
 	})
 }
 
+func TestAccVcsRootGit_AgentCheckoutSettings(t *testing.T) {
+	props := map[string]string{}
+	resName := "teamcity_vcs_root_git.git_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVcsRootGitDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVcsRootGitAgentCheckoutSettings,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVcsRootExists(resName, props),
+					testAccCheckVcsRootProperty(props, "agentCheckoutPolicy", "SHALLOW_CLONE"),
+					testAccCheckVcsRootProperty(props, "cloneDepth", "1"),
+					testAccCheckVcsRootProperty(props, "useLfs", "true"),
+					testAccCheckVcsRootProperty(props, "useSparseCheckout", "true"),
+					testAccCheckVcsRootProperty(props, "recursiveSubmodules", "false"),
+					testAccCheckVcsRootProperty(props, "serverSideAutoCrlf", "true"),
+					testAccCheckVcsRootProperty(props, "serverGitPath", "/opt/git/bin/git"),
+					testAccCheckVcsRootProperty(props, "useTrustedCertificates", "false"),
+					testAccCheckVcsRootProperty(props, "sslVerify", "false"),
+					testAccCheckVcsRootProperty(props, "ignoreKnownHosts", "false"),
+					resource.TestCheckResourceAttr(resName, "agent.#", "1"),
+					resource.TestCheckResourceAttr(resName, "agent.2648409189.checkout_policy", "shallow_clone"),
+					resource.TestCheckResourceAttr(resName, "agent.2648409189.clone_depth", "1"),
+					resource.TestCheckResourceAttr(resName, "agent.2648409189.lfs", "true"),
+					resource.TestCheckResourceAttr(resName, "agent.2648409189.sparse_checkout", "true"),
+					resource.TestCheckResourceAttr(resName, "agent.2648409189.submodule_recursion", "false"),
+					resource.TestCheckResourceAttr(resName, "convert_crlf", "true"),
+					resource.TestCheckResourceAttr(resName, "server_git_path", "/opt/git/bin/git"),
+					resource.TestCheckResourceAttr(resName, "ignore_known_hosts", "false"),
+				),
+			},
+			resource.TestStep{
+				Config:             testAccVcsRootGitAgentCheckoutSettings,
+				ExpectNonEmptyPlan: false,
+			},
+			resource.TestStep{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccVcsRootGit_DefaultAgentSettingsNoDiff(t *testing.T) {
+	resName := "teamcity_vcs_root_git.git_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVcsRootGitDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVcsRootGitBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "agent.#", "0"),
+					resource.TestCheckResourceAttr(resName, "ignore_known_hosts", "true"),
+				),
+			},
+			resource.TestStep{
+				Config:             testAccVcsRootGitBasic,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
 func TestAccVcsRootGit_Delete(t *testing.T) {
 	resName := "teamcity_vcs_root_git.git_test"
 
@@ -205,9 +274,97 @@ func TestAccVcsRootGit_Delete(t *testing.T) {
 	})
 }
 
+func TestAccVcsRootGit_AccessTokenAuth(t *testing.T) {
+	props := map[string]string{}
+	resName := "teamcity_vcs_root_git.git_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVcsRootGitDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVcsRootGitAccessToken,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVcsRootExists(resName, props),
+					testAccCheckVcsRootProperty(props, "authMethod", "PASSWORD"),
+					testAccCheckVcsRootProperty(props, "username", "oauth2"),
+					resource.TestCheckResourceAttr(resName, "auth.#", "1"),
+				),
+			},
+			resource.TestStep{
+				Config:             testAccVcsRootGitAccessToken,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
+func TestVcsRootGit_AuthValidation(t *testing.T) {
+	p := testProviderWithHandler(t, http.NotFoundHandler())
+	r := p.ResourcesMap["teamcity_vcs_root_git"]
+
+	cases := map[string]struct {
+		auth     map[string]interface{}
+		expected string
+	}{
+		"access token with password": {
+			auth:     map[string]interface{}{"type": "access_token", "username": "oauth2", "access_token": "ghp_secret", "password": "secret"},
+			expected: "'password' cannot be set with auth type 'access_token'",
+		},
+		"access token without token": {
+			auth:     map[string]interface{}{"type": "access_token", "username": "oauth2"},
+			expected: "auth type 'access_token' requires 'access_token' to be set",
+		},
+		"token id with ssh": {
+			auth:     map[string]interface{}{"type": "ssh", "ssh_type": "defaultKey", "token_id": "tc_token_id:CID_1:-1:1"},
+			expected: "'token_id' cannot be set with auth type 'ssh'",
+		},
+		// Checks of the auth types predating them only warn, to keep existing configurations working
+		"userpass without username": {
+			auth: map[string]interface{}{"type": "userpass", "password": "secret"},
+		},
+		"anonymous with password": {
+			auth: map[string]interface{}{"type": "anonymous", "username": "admin", "password": "secret"},
+		},
+		"uploaded key without key": {
+			auth: map[string]interface{}{"type": "ssh", "ssh_type": "uploadedKey"},
+		},
+		"refreshable token on old server": {
+			auth:     map[string]interface{}{"type": "token", "token_id": "tc_token_id:CID_1:-1:1"},
+			expected: "auth type 'token' requires TeamCity 2022.10 or newer, but the server runs 2020.1.3",
+		},
+		"access token": {
+			auth: map[string]interface{}{"type": "access_token", "username": "oauth2", "access_token": "ghp_secret"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := terraform.NewResourceConfigRaw(map[string]interface{}{
+				"name":           "application",
+				"project_id":     "Project",
+				"fetch_url":      "https://github.com/leidruid/terraform-provider-teamcity",
+				"default_branch": "refs/heads/master",
+				"auth":           []interface{}{c.auth},
+			})
+
+			_, err := r.Diff(nil, cfg, p.Meta())
+			if c.expected == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Fatalf("expected error '%s', got: %v", c.expected, err)
+			}
+		})
+	}
+}
+
 func testAccCheckVcsRootGitExists(name string, out *api.GitVcsRoot) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccClient()
 		return vcsRootGitExistsHelper(s, client, out)
 	}
 }
@@ -231,7 +388,7 @@ func vcsRootGitExistsHelper(s *terraform.State, client *api.Client, out *api.Git
 }
 
 func testAccCheckVcsRootGitDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*api.Client)
+	client := testAccClient()
 	return vcsRootGitDestroyHelper(s, client)
 }
 
@@ -257,7 +414,7 @@ func vcsRootGitDestroyHelper(s *terraform.State, client *api.Client) error {
 
 func testAccCheckVcsRootGitAgentSettings(vcs *api.GitVcsRoot, expected map[string]string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccClient()
 		dt, err := client.VcsRoots.GetByID((*vcs).ID)
 		if err != nil {
 			return err
@@ -397,3 +554,52 @@ resource "teamcity_vcs_root_git" "git_test" {
 	}
 }
 `
+
+const testAccVcsRootGitAgentCheckoutSettings = `
+resource "teamcity_project" "vcs_root_project" {
+  name = "vcs_root_project"
+}
+
+resource "teamcity_vcs_root_git" "git_test" {
+	name = "application"
+	project_id = "${teamcity_project.vcs_root_project.id}"
+	fetch_url = "https://github.com/leidruid/terraform-provider-teamcity"
+	default_branch = "refs/head/master"
+
+	convert_crlf = true
+	server_git_path = "/opt/git/bin/git"
+	use_trusted_ssl_certificates = false
+	ssl_verify = false
+	ignore_known_hosts = false
+
+	agent {
+		clean_policy = "always"
+		clean_files_policy = "untracked"
+		use_mirrors = false
+		checkout_policy = "shallow_clone"
+		clone_depth = 1
+		lfs = true
+		sparse_checkout = true
+		submodule_recursion = false
+	}
+}
+`
+
+const testAccVcsRootGitAccessToken = `
+resource "teamcity_project" "vcs_root_project" {
+  name = "vcs_root_project"
+}
+
+resource "teamcity_vcs_root_git" "git_test" {
+	name = "application"
+	project_id = teamcity_project.vcs_root_project.id
+	fetch_url = "https://github.com/leidruid/terraform-provider-teamcity"
+	default_branch = "refs/head/master"
+
+	auth {
+		type = "access_token"
+		username = "oauth2"
+		access_token = "ghp_secret"
+	}
+}
+`
@@ -0,0 +1,238 @@
+package teamcity
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	api "github.com/leidruid/go-teamcity/teamcity"
+)
+
+const vcsNameMercurial = "mercurial"
+
+func resourceVcsRootMercurial() *schema.Resource {
+	return &schema.Resource{
+		Create: createContext(resourceVcsRootMercurialCreate),
+		Read:   readContext(resourceVcsRootMercurialRead),
+		Update: updateContext(resourceVcsRootMercurialUpdate),
+		Delete: deleteContext(resourceVcsRootMercurialDelete),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name to identify this Mercurial VCS Root.",
+			},
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The ID for the parent project for this VCS Root. Required.",
+			},
+			"modification_check_interval": {
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntAtLeast(1),
+				Optional:     true,
+				Description:  "Specifies how often TeamCity polls the VCS repository for VCS changes (in seconds)",
+			},
+			"repository_url": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "URL of the repository, e.g. https://hg.example.com/repo or ssh://hg@hg.example.com/repo",
+			},
+			"default_branch": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+				Description: "Name of the default branch or bookmark",
+			},
+			"branches": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Branches to monitor besides the default with a set of rules in the form of +|-:branch_name (with the optional * placeholder)",
+			},
+			"enable_branch_spec_tags": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, tags can be used in the branch specification",
+			},
+			"hg_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "hg",
+				Description: "The path to the hg executable on the server and agents",
+			},
+			"auth": {
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Optional:    true,
+				Description: "Authentication configuration for VCS Root. If not specified, defaults to anonymous auth.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"username": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"password": {
+							Type:      schema.TypeString,
+							Optional:  true,
+							Sensitive: true,
+						},
+					},
+				},
+			},
+			"detect_subrepo_changes": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, changes in subrepositories are detected and shown as changes of the build",
+			},
+			"include_subrepos_in_patch": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "If true, subrepositories are included in the sources checked out on the server",
+			},
+			"username_style": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "userid",
+				ValidateFunc: validation.StringInSlice([]string{"userid", "author_name", "author_email", "author_full"}, true),
+				Description:  "Defines a way TeamCity binds VCS changes to the user. Allowed values: 'userid', 'author_name', 'author_email', 'author_full'",
+			},
+			"username_for_tags": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "User name used when labeling sources",
+			},
+		},
+	}
+}
+
+func resourceVcsRootMercurialCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	log.Printf("[INFO] detected new VCS Root resource, creating.")
+	created, err := client.createVcsRoot(newVcsRoot(d, vcsNameMercurial, expandMercurialVcsRootProperties(d)))
+	if err != nil {
+		return err
+	}
+	d.SetId(created.ID)
+
+	return resourceVcsRootMercurialRead(ctx, d, meta)
+}
+
+func resourceVcsRootMercurialUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	log.Printf("[INFO] Updating VCS Root resource.")
+	if err := client.updateVcsRoot(d, newVcsRoot(d, vcsNameMercurial, expandMercurialVcsRootProperties(d))); err != nil {
+		return err
+	}
+
+	return resourceVcsRootMercurialRead(ctx, d, meta)
+}
+
+func resourceVcsRootMercurialRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	dt, err := readVcsRoot(d, meta.(*Client), vcsNameMercurial)
+	if dt == nil {
+		return err
+	}
+	props := dt.Properties.Map()
+
+	if err := d.Set("repository_url", props["repositoryPath"]); err != nil {
+		return err
+	}
+	if v := props["branchName"]; v != "" {
+		if err := d.Set("default_branch", v); err != nil {
+			return err
+		}
+	}
+	if err := d.Set("branches", splitLines(props["teamcity:branchSpec"])); err != nil {
+		return err
+	}
+	if err := d.Set("enable_branch_spec_tags", boolProperty(props, "useTagsAsBranches", false)); err != nil {
+		return err
+	}
+	if v := props["hgCommandPath"]; v != "" {
+		if err := d.Set("hg_path", v); err != nil {
+			return err
+		}
+	}
+	if err := d.Set("auth", flattenMercurialVcsRootAuth(d, props)); err != nil {
+		return err
+	}
+	if err := d.Set("detect_subrepo_changes", boolProperty(props, "detectSubrepoChanges", false)); err != nil {
+		return err
+	}
+	if err := d.Set("include_subrepos_in_patch", boolProperty(props, "includeSubreposInPatch", true)); err != nil {
+		return err
+	}
+	if v, ok := flattenUsernameStyleMap[props["usernameStyle"]]; ok {
+		if err := d.Set("username_style", v); err != nil {
+			return err
+		}
+	}
+	if err := d.Set("username_for_tags", props["tagUsername"]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func resourceVcsRootMercurialDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	return resourceVcsRootDelete(meta.(*Client), d.Id())
+}
+
+func expandMercurialVcsRootProperties(d *schema.ResourceData) *api.Properties {
+	props := api.NewPropertiesEmpty()
+
+	props.AddOrReplaceValue("repositoryPath", d.Get("repository_url").(string))
+	props.AddOrReplaceValue("branchName", d.Get("default_branch").(string))
+	if v, ok := d.GetOk("branches"); ok {
+		props.AddOrReplaceValue("teamcity:branchSpec", strings.Join(expandStringSlice(v.([]interface{})), "\n"))
+	}
+	props.AddOrReplaceValue("useTagsAsBranches", strconv.FormatBool(d.Get("enable_branch_spec_tags").(bool)))
+	props.AddOrReplaceValue("hgCommandPath", d.Get("hg_path").(string))
+
+	if v, ok := d.GetOk("auth"); ok {
+		auth := v.([]interface{})[0].(map[string]interface{})
+		setProperty(props, "username", auth["username"].(string))
+		setProperty(props, "secure:password", auth["password"].(string))
+	}
+
+	props.AddOrReplaceValue("detectSubrepoChanges", strconv.FormatBool(d.Get("detect_subrepo_changes").(bool)))
+	props.AddOrReplaceValue("includeSubreposInPatch", strconv.FormatBool(d.Get("include_subrepos_in_patch").(bool)))
+	props.AddOrReplaceValue("usernameStyle", expandUsernameStyleMap[strings.ToLower(d.Get("username_style").(string))])
+	setProperty(props, "tagUsername", d.Get("username_for_tags").(string))
+
+	return props
+}
+
+func flattenMercurialVcsRootAuth(d *schema.ResourceData, props map[string]string) []map[string]interface{} {
+	if props["username"] == "" {
+		return nil
+	}
+
+	m := make(map[string]interface{})
+	m["username"] = props["username"]
+
+	//Set back password if contained in state, since TeamCity doesn't return secure properties
+	if v, ok := d.GetOk("auth.0.password"); ok {
+		m["password"] = v.(string)
+	}
+
+	return []map[string]interface{}{m}
+}
@@ -0,0 +1,116 @@
+package teamcity_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccVcsRootMercurial_Basic(t *testing.T) {
+	props := map[string]string{}
+	resName := "teamcity_vcs_root_mercurial.hg_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVcsRootDestroy("teamcity_vcs_root_mercurial"),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVcsRootMercurialBasic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVcsRootExists(resName, props),
+					testAccCheckVcsRootProperty(props, "repositoryPath", "https://hg.example.com/tools"),
+					testAccCheckVcsRootProperty(props, "branchName", "default"),
+					testAccCheckVcsRootProperty(props, "usernameStyle", "USERID"),
+					resource.TestCheckResourceAttr(resName, "name", "tools"),
+					resource.TestCheckResourceAttr(resName, "hg_path", "hg"),
+					resource.TestCheckResourceAttr(resName, "include_subrepos_in_patch", "true"),
+					resource.TestCheckResourceAttr(resName, "auth.#", "0"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccVcsRootMercurial_UpdateInPlace(t *testing.T) {
+	props := map[string]string{}
+	resName := "teamcity_vcs_root_mercurial.hg_test"
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVcsRootDestroy("teamcity_vcs_root_mercurial"),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVcsRootMercurialBasic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVcsRootExists(resName, props),
+					testAccStoreID(resName, &id),
+				),
+			},
+			resource.TestStep{
+				Config: testAccVcsRootMercurialUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVcsRootExists(resName, props),
+					testAccCheckSameID(resName, &id),
+					testAccCheckVcsRootProperty(props, "branchName", "stable"),
+					testAccCheckVcsRootProperty(props, "teamcity:branchSpec", "+:*\n-:experimental"),
+					testAccCheckVcsRootProperty(props, "username", "builder"),
+					testAccCheckVcsRootProperty(props, "detectSubrepoChanges", "true"),
+					testAccCheckVcsRootProperty(props, "usernameStyle", "EMAIL"),
+					resource.TestCheckResourceAttr(resName, "branches.#", "2"),
+					resource.TestCheckResourceAttr(resName, "hg_path", "/usr/bin/hg"),
+					resource.TestCheckResourceAttr(resName, "auth.0.username", "builder"),
+					resource.TestCheckResourceAttr(resName, "auth.0.password", "secret"),
+					resource.TestCheckResourceAttr(resName, "username_style", "author_email"),
+				),
+			},
+			resource.TestStep{
+				Config:             testAccVcsRootMercurialUpdated,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
+const testAccVcsRootMercurialBasic = `
+resource "teamcity_project" "vcs_root_project" {
+  name = "vcs_root_mercurial_project"
+}
+
+resource "teamcity_vcs_root_mercurial" "hg_test" {
+	name = "tools"
+	project_id = teamcity_project.vcs_root_project.id
+	repository_url = "https://hg.example.com/tools"
+}
+`
+
+const testAccVcsRootMercurialUpdated = `
+resource "teamcity_project" "vcs_root_project" {
+  name = "vcs_root_mercurial_project"
+}
+
+resource "teamcity_vcs_root_mercurial" "hg_test" {
+	name = "tools"
+	project_id = teamcity_project.vcs_root_project.id
+	repository_url = "https://hg.example.com/tools"
+	default_branch = "stable"
+	branches = [
+		"+:*",
+		"-:experimental",
+	]
+	hg_path = "/usr/bin/hg"
+
+	auth {
+		username = "builder"
+		password = "secret"
+	}
+
+	detect_subrepo_changes = true
+	username_style = "author_email"
+}
+`
@@ -0,0 +1,217 @@
+package teamcity
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	api "github.com/leidruid/go-teamcity/teamcity"
+)
+
+const vcsNamePerforce = "perforce"
+
+func resourceVcsRootPerforce() *schema.Resource {
+	return &schema.Resource{
+		Create: createContext(resourceVcsRootPerforceCreate),
+		Read:   readContext(resourceVcsRootPerforceRead),
+		Update: updateContext(resourceVcsRootPerforceUpdate),
+		Delete: deleteContext(resourceVcsRootPerforceDelete),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name to identify this Perforce VCS Root.",
+			},
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The ID for the parent project for this VCS Root. Required.",
+			},
+			"modification_check_interval": {
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntAtLeast(1),
+				Optional:     true,
+				Description:  "Specifies how often TeamCity polls the VCS repository for VCS changes (in seconds)",
+			},
+			"port": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Perforce server address (P4PORT), e.g. ssl:perforce.example.com:1666",
+			},
+			"stream": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"stream", "client_mapping"},
+				Description:  "Depot path of the stream to check out, e.g. //streams/main",
+			},
+			"client_mapping": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ExactlyOneOf: []string{"stream", "client_mapping"},
+				Description:  "Client workspace view to check out, as lines in the form of //depot/path/... //team-city-agent/path/...",
+			},
+			"username": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Password or ticket of the user",
+			},
+			"use_ticket_auth": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, TeamCity logs in with 'p4 login' and uses ticket-based authentication",
+			},
+			"charset": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "none",
+				Description: "Character set (P4CHARSET) used by a Unicode server, e.g. utf8. Use 'none' for non-Unicode servers.",
+			},
+			"label_to_sync": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Label or changelist to check out instead of the latest revision",
+			},
+			"agent": {
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Optional:    true,
+				Description: "Agent settings for the VCS Root",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"p4_path": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The path to the p4 executable on the agent",
+						},
+						"workspace_options": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Options of the workspace created on the agent, as lines of the p4 client spec, e.g. 'Options: noallwrite clobber nocompress unlocked nomodtime rmdir'",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceVcsRootPerforceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	log.Printf("[INFO] detected new VCS Root resource, creating.")
+	created, err := client.createVcsRoot(newVcsRoot(d, vcsNamePerforce, expandPerforceVcsRootProperties(d)))
+	if err != nil {
+		return err
+	}
+	d.SetId(created.ID)
+
+	return resourceVcsRootPerforceRead(ctx, d, meta)
+}
+
+func resourceVcsRootPerforceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	log.Printf("[INFO] Updating VCS Root resource.")
+	if err := client.updateVcsRoot(d, newVcsRoot(d, vcsNamePerforce, expandPerforceVcsRootProperties(d))); err != nil {
+		return err
+	}
+
+	return resourceVcsRootPerforceRead(ctx, d, meta)
+}
+
+func resourceVcsRootPerforceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	dt, err := readVcsRoot(d, meta.(*Client), vcsNamePerforce)
+	if dt == nil {
+		return err
+	}
+	props := dt.Properties.Map()
+
+	if err := d.Set("port", props["port"]); err != nil {
+		return err
+	}
+	if err := d.Set("stream", props["stream"]); err != nil {
+		return err
+	}
+	if err := d.Set("client_mapping", splitLines(props["client-mapping"])); err != nil {
+		return err
+	}
+	if err := d.Set("username", props["user"]); err != nil {
+		return err
+	}
+	if err := d.Set("use_ticket_auth", boolProperty(props, "use-login", false)); err != nil {
+		return err
+	}
+	if v := props["charset"]; v != "" {
+		if err := d.Set("charset", v); err != nil {
+			return err
+		}
+	}
+	if err := d.Set("label_to_sync", props["label"]); err != nil {
+		return err
+	}
+	if err := d.Set("agent", flattenPerforceAgentSettings(props)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func resourceVcsRootPerforceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	return resourceVcsRootDelete(meta.(*Client), d.Id())
+}
+
+func expandPerforceVcsRootProperties(d *schema.ResourceData) *api.Properties {
+	props := api.NewPropertiesEmpty()
+
+	props.AddOrReplaceValue("port", d.Get("port").(string))
+	setProperty(props, "stream", d.Get("stream").(string))
+	if v, ok := d.GetOk("client_mapping"); ok {
+		props.AddOrReplaceValue("client-mapping", strings.Join(expandStringSlice(v.([]interface{})), "\n"))
+	}
+
+	setProperty(props, "user", d.Get("username").(string))
+	setProperty(props, "secure:passwd", d.Get("password").(string))
+	props.AddOrReplaceValue("use-login", strconv.FormatBool(d.Get("use_ticket_auth").(bool)))
+	props.AddOrReplaceValue("charset", d.Get("charset").(string))
+	setProperty(props, "label", d.Get("label_to_sync").(string))
+
+	if v, ok := d.GetOk("agent"); ok && v.([]interface{})[0] != nil {
+		agent := v.([]interface{})[0].(map[string]interface{})
+		setProperty(props, "p4-exe", agent["p4_path"].(string))
+		setProperty(props, "workspace-options", strings.Join(expandStringSlice(agent["workspace_options"].([]interface{})), "\n"))
+	}
+
+	return props
+}
+
+func flattenPerforceAgentSettings(props map[string]string) []map[string]interface{} {
+	p4Path, workspaceOptions := props["p4-exe"], props["workspace-options"]
+	if p4Path == "" && workspaceOptions == "" {
+		return nil
+	}
+
+	m := make(map[string]interface{})
+	m["p4_path"] = p4Path
+	m["workspace_options"] = splitLines(workspaceOptions)
+	return []map[string]interface{}{m}
+}
@@ -0,0 +1,122 @@
+package teamcity_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccVcsRootPerforce_Stream(t *testing.T) {
+	props := map[string]string{}
+	resName := "teamcity_vcs_root_perforce.p4_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVcsRootDestroy("teamcity_vcs_root_perforce"),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVcsRootPerforceStream,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVcsRootExists(resName, props),
+					testAccCheckVcsRootProperty(props, "port", "ssl:perforce.example.com:1666"),
+					testAccCheckVcsRootProperty(props, "stream", "//assets/main"),
+					testAccCheckVcsRootProperty(props, "user", "builder"),
+					testAccCheckVcsRootProperty(props, "charset", "utf8"),
+					resource.TestCheckResourceAttr(resName, "name", "assets"),
+					resource.TestCheckResourceAttr(resName, "stream", "//assets/main"),
+					resource.TestCheckResourceAttr(resName, "client_mapping.#", "0"),
+					resource.TestCheckResourceAttr(resName, "use_ticket_auth", "true"),
+					resource.TestCheckResourceAttr(resName, "password", "secret"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:            resName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password"},
+			},
+		},
+	})
+}
+
+func TestAccVcsRootPerforce_UpdateToClientMapping(t *testing.T) {
+	props := map[string]string{}
+	resName := "teamcity_vcs_root_perforce.p4_test"
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVcsRootDestroy("teamcity_vcs_root_perforce"),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVcsRootPerforceStream,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVcsRootExists(resName, props),
+					testAccStoreID(resName, &id),
+				),
+			},
+			resource.TestStep{
+				Config: testAccVcsRootPerforceClientMapping,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVcsRootExists(resName, props),
+					testAccCheckSameID(resName, &id),
+					testAccCheckVcsRootProperty(props, "stream", ""),
+					testAccCheckVcsRootProperty(props, "client-mapping", "//depot/assets/... //team-city-agent/assets/...\n-//depot/assets/raw/... //team-city-agent/assets/raw/..."),
+					testAccCheckVcsRootProperty(props, "p4-exe", "/usr/local/bin/p4"),
+					testAccCheckVcsRootProperty(props, "label", "release_1_0"),
+					resource.TestCheckResourceAttr(resName, "stream", ""),
+					resource.TestCheckResourceAttr(resName, "client_mapping.#", "2"),
+					resource.TestCheckResourceAttr(resName, "charset", "none"),
+					resource.TestCheckResourceAttr(resName, "agent.0.workspace_options.#", "1"),
+				),
+			},
+			resource.TestStep{
+				Config:             testAccVcsRootPerforceClientMapping,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
+const testAccVcsRootPerforceStream = `
+resource "teamcity_project" "vcs_root_project" {
+  name = "vcs_root_perforce_project"
+}
+
+resource "teamcity_vcs_root_perforce" "p4_test" {
+	name = "assets"
+	project_id = teamcity_project.vcs_root_project.id
+	port = "ssl:perforce.example.com:1666"
+	stream = "//assets/main"
+	username = "builder"
+	password = "secret"
+	use_ticket_auth = true
+	charset = "utf8"
+}
+`
+
+const testAccVcsRootPerforceClientMapping = `
+resource "teamcity_project" "vcs_root_project" {
+  name = "vcs_root_perforce_project"
+}
+
+resource "teamcity_vcs_root_perforce" "p4_test" {
+	name = "assets"
+	project_id = teamcity_project.vcs_root_project.id
+	port = "ssl:perforce.example.com:1666"
+	client_mapping = [
+		"//depot/assets/... //team-city-agent/assets/...",
+		"-//depot/assets/raw/... //team-city-agent/assets/raw/...",
+	]
+	username = "builder"
+	password = "secret"
+	label_to_sync = "release_1_0"
+
+	agent {
+		p4_path = "/usr/local/bin/p4"
+		workspace_options = [
+			"Options: noallwrite clobber nocompress unlocked nomodtime rmdir",
+		]
+	}
+}
+`
@@ -0,0 +1,285 @@
+package teamcity
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	api "github.com/leidruid/go-teamcity/teamcity"
+)
+
+const vcsNameSvn = "svn"
+
+func resourceVcsRootSvn() *schema.Resource {
+	return &schema.Resource{
+		Create: createContext(resourceVcsRootSvnCreate),
+		Read:   readContext(resourceVcsRootSvnRead),
+		Update: updateContext(resourceVcsRootSvnUpdate),
+		Delete: deleteContext(resourceVcsRootSvnDelete),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name to identify this Subversion VCS Root.",
+			},
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The ID for the parent project for this VCS Root. Required.",
+			},
+			"modification_check_interval": {
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntAtLeast(1),
+				Optional:     true,
+				Description:  "Specifies how often TeamCity polls the VCS repository for VCS changes (in seconds)",
+			},
+			"url": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "URL of the repository, e.g. https://svn.example.com/repo/trunk or svn+ssh://svn.example.com/repo/trunk",
+			},
+			"auth": {
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Optional:    true,
+				Description: "Authentication configuration for VCS Root. If not specified, defaults to anonymous auth.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:         schema.TypeString,
+							ValidateFunc: validation.StringInSlice([]string{"userpass", "ssh"}, false),
+							Required:     true,
+						},
+						"username": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"password": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Password if using 'userpass' auth. Private key passphrase if using 'ssh' auth.",
+						},
+						"ssh_type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"uploadedKey", "customKey"}, false),
+							Description:  "If using SSH, this field specifies how the SSH Key will be sourced.",
+						},
+						"key_spec": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.NoZeroValues,
+							Description:  "For 'customKey' refers to the path to a private key. For 'uploadedKey', corresponds to the name of the SSH Key uploaded into the project.",
+						},
+						"ssh_port": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      22,
+							ValidateFunc: validation.IsPortNumber,
+						},
+					},
+				},
+			},
+			"externals_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "full",
+				ValidateFunc: validation.StringInSlice([]string{"full", "checkout_ignore_changes", "none"}, false),
+				Description:  "Defines how svn:externals are handled. Allowed values: 'full', 'checkout_ignore_changes', 'none'",
+			},
+			"working_copy_format": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "1.8",
+				ValidateFunc: validation.StringInSlice([]string{"1.4", "1.5", "1.6", "1.7", "1.8"}, false),
+				Description:  "Format of the working copy created on agents",
+			},
+			"labeling_rules": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Repository layout used to create labels, as rules in the form of trunk_or_branch_path=>tags_path. Ex: trunk=>tags",
+			},
+			"labeling_message": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Commit message used when creating labels",
+			},
+			"config_dir": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to the Subversion configuration directory. If not specified, the default one is used.",
+			},
+			"enable_non_trusted_ssl": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "If true, connections to servers with non-trusted SSL certificates are accepted",
+			},
+		},
+	}
+}
+
+var expandSvnExternalsModeMap = map[string]string{
+	"full":                    "externals-full",
+	"checkout_ignore_changes": "externals-checkout",
+	"none":                    "externals-none",
+}
+
+var flattenSvnExternalsModeMap = reverseMap(expandSvnExternalsModeMap)
+
+var expandSvnSSHTypeMap = map[string]string{
+	"uploadedKey": "teamcitySshKey",
+	"customKey":   "ssh-key-file",
+}
+
+func resourceVcsRootSvnCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	log.Printf("[INFO] detected new VCS Root resource, creating.")
+	created, err := client.createVcsRoot(newVcsRoot(d, vcsNameSvn, expandSvnVcsRootProperties(d)))
+	if err != nil {
+		return err
+	}
+	d.SetId(created.ID)
+
+	return resourceVcsRootSvnRead(ctx, d, meta)
+}
+
+func resourceVcsRootSvnUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	log.Printf("[INFO] Updating VCS Root resource.")
+	if err := client.updateVcsRoot(d, newVcsRoot(d, vcsNameSvn, expandSvnVcsRootProperties(d))); err != nil {
+		return err
+	}
+
+	return resourceVcsRootSvnRead(ctx, d, meta)
+}
+
+func resourceVcsRootSvnRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	dt, err := readVcsRoot(d, meta.(*Client), vcsNameSvn)
+	if dt == nil {
+		return err
+	}
+	props := dt.Properties.Map()
+
+	if err := d.Set("url", props["url"]); err != nil {
+		return err
+	}
+	if err := d.Set("auth", flattenSvnVcsRootAuth(d, props)); err != nil {
+		return err
+	}
+	if v, ok := flattenSvnExternalsModeMap[props["externals-mode"]]; ok {
+		if err := d.Set("externals_mode", v); err != nil {
+			return err
+		}
+	}
+	if v := props["working-copy-format"]; v != "" {
+		if err := d.Set("working_copy_format", v); err != nil {
+			return err
+		}
+	}
+	if err := d.Set("labeling_rules", splitLines(props["labelingPatterns"])); err != nil {
+		return err
+	}
+	if err := d.Set("labeling_message", props["labelingMessage"]); err != nil {
+		return err
+	}
+	if boolProperty(props, "svn-use-default-config-directory", true) {
+		if err := d.Set("config_dir", ""); err != nil {
+			return err
+		}
+	} else if err := d.Set("config_dir", props["svn-config-directory"]); err != nil {
+		return err
+	}
+	if err := d.Set("enable_non_trusted_ssl", boolProperty(props, "enable-unsafe-ssl", true)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func resourceVcsRootSvnDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	return resourceVcsRootDelete(meta.(*Client), d.Id())
+}
+
+func expandSvnVcsRootProperties(d *schema.ResourceData) *api.Properties {
+	props := api.NewPropertiesEmpty()
+
+	props.AddOrReplaceValue("url", d.Get("url").(string))
+	props.AddOrReplaceValue("externals-mode", expandSvnExternalsModeMap[d.Get("externals_mode").(string)])
+	props.AddOrReplaceValue("working-copy-format", d.Get("working_copy_format").(string))
+	props.AddOrReplaceValue("enable-unsafe-ssl", strconv.FormatBool(d.Get("enable_non_trusted_ssl").(bool)))
+
+	if v, ok := d.GetOk("labeling_rules"); ok {
+		props.AddOrReplaceValue("labelingPatterns", strings.Join(expandStringSlice(v.([]interface{})), "\n"))
+	}
+	setProperty(props, "labelingMessage", d.Get("labeling_message").(string))
+
+	if v, ok := d.GetOk("config_dir"); ok {
+		props.AddOrReplaceValue("svn-use-default-config-directory", "false")
+		props.AddOrReplaceValue("svn-config-directory", v.(string))
+	} else {
+		props.AddOrReplaceValue("svn-use-default-config-directory", "true")
+	}
+
+	if v, ok := d.GetOk("auth"); ok {
+		auth := v.([]interface{})[0].(map[string]interface{})
+		setProperty(props, "user", auth["username"].(string))
+
+		switch auth["type"].(string) {
+		case "userpass":
+			setProperty(props, "secure:svn-password", auth["password"].(string))
+		case "ssh":
+			setProperty(props, expandSvnSSHTypeMap[auth["ssh_type"].(string)], auth["key_spec"].(string))
+			setProperty(props, "secure:passphrase", auth["password"].(string))
+			props.AddOrReplaceValue("ssh-port", strconv.Itoa(auth["ssh_port"].(int)))
+		}
+	}
+
+	return props
+}
+
+func flattenSvnVcsRootAuth(d *schema.ResourceData, props map[string]string) []map[string]interface{} {
+	m := map[string]interface{}{"ssh_port": 22}
+
+	if port, ok := props["ssh-port"]; ok {
+		m["type"] = "ssh"
+		m["ssh_port"], _ = strconv.Atoi(port)
+		for sshType, name := range expandSvnSSHTypeMap {
+			if v, ok := props[name]; ok {
+				m["ssh_type"] = sshType
+				m["key_spec"] = v
+			}
+		}
+	} else if props["user"] != "" {
+		m["type"] = "userpass"
+	} else {
+		return nil
+	}
+
+	if props["user"] != "" {
+		m["username"] = props["user"]
+	}
+
+	//Set back password if contained in state, since TeamCity doesn't return secure properties
+	if v, ok := d.GetOk("auth.0.password"); ok {
+		m["password"] = v.(string)
+	}
+
+	return []map[string]interface{}{m}
+}
@@ -0,0 +1,244 @@
+package teamcity_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/leidruid/terraform-provider-teamcity/teamcity"
+)
+
+func TestAccVcsRootSvn_Basic(t *testing.T) {
+	props := map[string]string{}
+	resName := "teamcity_vcs_root_svn.svn_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVcsRootDestroy("teamcity_vcs_root_svn"),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVcsRootSvnBasic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVcsRootExists(resName, props),
+					testAccCheckVcsRootProperty(props, "url", "https://svn.example.com/repo/trunk"),
+					testAccCheckVcsRootProperty(props, "externals-mode", "externals-full"),
+					resource.TestCheckResourceAttr(resName, "name", "legacy"),
+					resource.TestCheckResourceAttr(resName, "project_id", "VcsRootSvnProject"),
+					resource.TestCheckResourceAttr(resName, "externals_mode", "full"),
+					resource.TestCheckResourceAttr(resName, "working_copy_format", "1.8"),
+					resource.TestCheckResourceAttr(resName, "enable_non_trusted_ssl", "true"),
+					resource.TestCheckResourceAttr(resName, "auth.#", "0"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccVcsRootSvn_UpdateInPlace(t *testing.T) {
+	props := map[string]string{}
+	resName := "teamcity_vcs_root_svn.svn_test"
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVcsRootDestroy("teamcity_vcs_root_svn"),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVcsRootSvnBasic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVcsRootExists(resName, props),
+					testAccStoreID(resName, &id),
+				),
+			},
+			resource.TestStep{
+				Config: testAccVcsRootSvnUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVcsRootExists(resName, props),
+					testAccCheckSameID(resName, &id),
+					testAccCheckVcsRootProperty(props, "url", "svn+ssh://svn.example.com/repo/branches/release"),
+					testAccCheckVcsRootProperty(props, "teamcitySshKey", "svnKey"),
+					testAccCheckVcsRootProperty(props, "ssh-port", "2222"),
+					testAccCheckVcsRootProperty(props, "externals-mode", "externals-none"),
+					testAccCheckVcsRootProperty(props, "labelingPatterns", "trunk=>tags\nbranches/#=>tags"),
+					testAccCheckVcsRootProperty(props, "svn-use-default-config-directory", "false"),
+					resource.TestCheckResourceAttr(resName, "name", "legacy_updated"),
+					resource.TestCheckResourceAttr(resName, "modification_check_interval", "120"),
+					resource.TestCheckResourceAttr(resName, "auth.0.type", "ssh"),
+					resource.TestCheckResourceAttr(resName, "auth.0.ssh_type", "uploadedKey"),
+					resource.TestCheckResourceAttr(resName, "labeling_rules.#", "2"),
+					resource.TestCheckResourceAttr(resName, "working_copy_format", "1.7"),
+					resource.TestCheckResourceAttr(resName, "config_dir", "/etc/subversion"),
+				),
+			},
+			resource.TestStep{
+				Config:             testAccVcsRootSvnUpdated,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
+func TestAccVcsRootSvn_UserpassAuth(t *testing.T) {
+	props := map[string]string{}
+	resName := "teamcity_vcs_root_svn.svn_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVcsRootDestroy("teamcity_vcs_root_svn"),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVcsRootSvnUserpass,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVcsRootExists(resName, props),
+					testAccCheckVcsRootProperty(props, "user", "builder"),
+					resource.TestCheckResourceAttr(resName, "auth.0.type", "userpass"),
+					resource.TestCheckResourceAttr(resName, "auth.0.password", "secret"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:            resName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"auth.0.password"},
+			},
+		},
+	})
+}
+
+// testAccCheckVcsRootExists retrieves the properties of a VCS Root of any type into out
+func testAccCheckVcsRootExists(n string, out map[string]string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		client := testAccProvider.Meta().(*teamcity.Client)
+		props, err := client.VcsRootProperties(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("Received an error retrieving VCS Root: %s", err)
+		}
+
+		for k := range out {
+			delete(out, k)
+		}
+		for k, v := range props {
+			out[k] = v
+		}
+		return nil
+	}
+}
+
+func testAccCheckVcsRootProperty(props map[string]string, name string, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if props[name] != expected {
+			return fmt.Errorf("VCS Root property %s: got '%s', expected '%s'", name, props[name], expected)
+		}
+		return nil
+	}
+}
+
+func testAccCheckVcsRootDestroy(resourceType string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*teamcity.Client)
+		for _, r := range s.RootModule().Resources {
+			if r.Type != resourceType {
+				continue
+			}
+
+			_, err := client.VcsRootProperties(r.Primary.ID)
+			if err != nil {
+				if teamcity.IsNotFound(err) {
+					continue
+				}
+				return fmt.Errorf("Received an error retrieving the VCS Root: %s", err)
+			}
+
+			return fmt.Errorf("VCS Root still exists")
+		}
+		return nil
+	}
+}
+
+func testAccStoreID(n string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		*id = s.RootModule().Resources[n].Primary.ID
+		return nil
+	}
+}
+
+// testAccCheckSameID verifies the resource was updated in-place instead of being recreated
+func testAccCheckSameID(n string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if actual := s.RootModule().Resources[n].Primary.ID; actual != *id {
+			return fmt.Errorf("%s was recreated: ID changed from '%s' to '%s'", n, *id, actual)
+		}
+		return nil
+	}
+}
+
+const testAccVcsRootSvnBasic = `
+resource "teamcity_project" "vcs_root_project" {
+  name = "vcs_root_svn_project"
+}
+
+resource "teamcity_vcs_root_svn" "svn_test" {
+	name = "legacy"
+	project_id = teamcity_project.vcs_root_project.id
+	url = "https://svn.example.com/repo/trunk"
+}
+`
+
+const testAccVcsRootSvnUpdated = `
+resource "teamcity_project" "vcs_root_project" {
+  name = "vcs_root_svn_project"
+}
+
+resource "teamcity_vcs_root_svn" "svn_test" {
+	name = "legacy_updated"
+	project_id = teamcity_project.vcs_root_project.id
+	url = "svn+ssh://svn.example.com/repo/branches/release"
+	modification_check_interval = 120
+
+	auth {
+		type = "ssh"
+		username = "builder"
+		ssh_type = "uploadedKey"
+		key_spec = "svnKey"
+		ssh_port = 2222
+	}
+
+	externals_mode = "none"
+	working_copy_format = "1.7"
+	labeling_rules = [
+		"trunk=>tags",
+		"branches/#=>tags",
+	]
+	config_dir = "/etc/subversion"
+	enable_non_trusted_ssl = false
+}
+`
+
+const testAccVcsRootSvnUserpass = `
+resource "teamcity_project" "vcs_root_project" {
+  name = "vcs_root_svn_project"
+}
+
+resource "teamcity_vcs_root_svn" "svn_test" {
+	name = "legacy"
+	project_id = teamcity_project.vcs_root_project.id
+	url = "https://svn.example.com/repo/trunk"
+
+	auth {
+		type = "userpass"
+		username = "builder"
+		password = "secret"
+	}
+}
+`
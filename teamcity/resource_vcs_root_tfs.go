@@ -0,0 +1,181 @@
+package teamcity
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	api "github.com/leidruid/go-teamcity/teamcity"
+)
+
+const vcsNameTfs = "tfs"
+
+func resourceVcsRootTfs() *schema.Resource {
+	return &schema.Resource{
+		Create: createContext(resourceVcsRootTfsCreate),
+		Read:   readContext(resourceVcsRootTfsRead),
+		Update: updateContext(resourceVcsRootTfsUpdate),
+		Delete: deleteContext(resourceVcsRootTfsDelete),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name to identify this TFVC VCS Root.",
+			},
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The ID for the parent project for this VCS Root. Required.",
+			},
+			"modification_check_interval": {
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntAtLeast(1),
+				Optional:     true,
+				Description:  "Specifies how often TeamCity polls the VCS repository for VCS changes (in seconds)",
+			},
+			"collection_url": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "URL of the project collection, e.g. https://dev.azure.com/organization or https://tfs.example.com/tfs/DefaultCollection",
+			},
+			"root_path": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Server path to check out, e.g. $/Project/Main",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "User to connect with, e.g. DOMAIN\\user. Leave empty to authenticate with a personal access token as password.",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Password or personal access token of the user",
+			},
+			"workspace": {
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Optional:    true,
+				Description: "Settings of the TFS workspaces created by TeamCity",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"location": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "server",
+							ValidateFunc: validation.StringInSlice([]string{"server", "local"}, false),
+							Description:  "Whether workspaces are server or local workspaces. Allowed values: 'server', 'local'",
+						},
+						"name_prefix": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Prefix of the names of workspaces created by TeamCity",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+var expandTfsWorkspaceLocationMap = map[string]string{
+	"server": "SERVER",
+	"local":  "LOCAL",
+}
+
+var flattenTfsWorkspaceLocationMap = reverseMap(expandTfsWorkspaceLocationMap)
+
+func resourceVcsRootTfsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	log.Printf("[INFO] detected new VCS Root resource, creating.")
+	created, err := client.createVcsRoot(newVcsRoot(d, vcsNameTfs, expandTfsVcsRootProperties(d)))
+	if err != nil {
+		return err
+	}
+	d.SetId(created.ID)
+
+	return resourceVcsRootTfsRead(ctx, d, meta)
+}
+
+func resourceVcsRootTfsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	log.Printf("[INFO] Updating VCS Root resource.")
+	if err := client.updateVcsRoot(d, newVcsRoot(d, vcsNameTfs, expandTfsVcsRootProperties(d))); err != nil {
+		return err
+	}
+
+	return resourceVcsRootTfsRead(ctx, d, meta)
+}
+
+func resourceVcsRootTfsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	dt, err := readVcsRoot(d, meta.(*Client), vcsNameTfs)
+	if dt == nil {
+		return err
+	}
+	props := dt.Properties.Map()
+
+	if err := d.Set("collection_url", props["tfs-url"]); err != nil {
+		return err
+	}
+	if err := d.Set("root_path", props["tfs-root"]); err != nil {
+		return err
+	}
+	if err := d.Set("username", props["tfs-username"]); err != nil {
+		return err
+	}
+	if err := d.Set("workspace", flattenTfsWorkspace(props)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func resourceVcsRootTfsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	return resourceVcsRootDelete(meta.(*Client), d.Id())
+}
+
+func expandTfsVcsRootProperties(d *schema.ResourceData) *api.Properties {
+	props := api.NewPropertiesEmpty()
+
+	props.AddOrReplaceValue("tfs-url", d.Get("collection_url").(string))
+	props.AddOrReplaceValue("tfs-root", d.Get("root_path").(string))
+	setProperty(props, "tfs-username", d.Get("username").(string))
+	setProperty(props, "secure:tfs-password", d.Get("password").(string))
+
+	if v, ok := d.GetOk("workspace"); ok && v.([]interface{})[0] != nil {
+		workspace := v.([]interface{})[0].(map[string]interface{})
+		props.AddOrReplaceValue("tfs-workspace-location", expandTfsWorkspaceLocationMap[workspace["location"].(string)])
+		setProperty(props, "tfs-workspace", workspace["name_prefix"].(string))
+	}
+
+	return props
+}
+
+func flattenTfsWorkspace(props map[string]string) []map[string]interface{} {
+	location, ok := flattenTfsWorkspaceLocationMap[props["tfs-workspace-location"]]
+	if !ok && props["tfs-workspace"] == "" {
+		return nil
+	}
+	if !ok {
+		location = "server"
+	}
+
+	m := make(map[string]interface{})
+	m["location"] = location
+	m["name_prefix"] = props["tfs-workspace"]
+	return []map[string]interface{}{m}
+}
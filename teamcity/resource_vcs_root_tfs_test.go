@@ -0,0 +1,108 @@
+package teamcity_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccVcsRootTfs_Basic(t *testing.T) {
+	props := map[string]string{}
+	resName := "teamcity_vcs_root_tfs.tfs_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVcsRootDestroy("teamcity_vcs_root_tfs"),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVcsRootTfsBasic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVcsRootExists(resName, props),
+					testAccCheckVcsRootProperty(props, "tfs-url", "https://dev.azure.com/example"),
+					testAccCheckVcsRootProperty(props, "tfs-root", "$/Billing/Main"),
+					testAccCheckVcsRootProperty(props, "tfs-username", ""),
+					resource.TestCheckResourceAttr(resName, "name", "billing"),
+					resource.TestCheckResourceAttr(resName, "password", "token"),
+					resource.TestCheckResourceAttr(resName, "workspace.#", "0"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:            resName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password"},
+			},
+		},
+	})
+}
+
+func TestAccVcsRootTfs_UpdateInPlace(t *testing.T) {
+	props := map[string]string{}
+	resName := "teamcity_vcs_root_tfs.tfs_test"
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVcsRootDestroy("teamcity_vcs_root_tfs"),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVcsRootTfsBasic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVcsRootExists(resName, props),
+					testAccStoreID(resName, &id),
+				),
+			},
+			resource.TestStep{
+				Config: testAccVcsRootTfsUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVcsRootExists(resName, props),
+					testAccCheckSameID(resName, &id),
+					testAccCheckVcsRootProperty(props, "tfs-root", "$/Billing/Release"),
+					testAccCheckVcsRootProperty(props, "tfs-username", "EXAMPLE\\builder"),
+					testAccCheckVcsRootProperty(props, "tfs-workspace-location", "LOCAL"),
+					testAccCheckVcsRootProperty(props, "tfs-workspace", "tc-billing"),
+					resource.TestCheckResourceAttr(resName, "workspace.0.location", "local"),
+					resource.TestCheckResourceAttr(resName, "workspace.0.name_prefix", "tc-billing"),
+				),
+			},
+			resource.TestStep{
+				Config:             testAccVcsRootTfsUpdated,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
+const testAccVcsRootTfsBasic = `
+resource "teamcity_project" "vcs_root_project" {
+  name = "vcs_root_tfs_project"
+}
+
+resource "teamcity_vcs_root_tfs" "tfs_test" {
+	name = "billing"
+	project_id = teamcity_project.vcs_root_project.id
+	collection_url = "https://dev.azure.com/example"
+	root_path = "$/Billing/Main"
+	password = "token"
+}
+`
+
+const testAccVcsRootTfsUpdated = `
+resource "teamcity_project" "vcs_root_project" {
+  name = "vcs_root_tfs_project"
+}
+
+resource "teamcity_vcs_root_tfs" "tfs_test" {
+	name = "billing"
+	project_id = teamcity_project.vcs_root_project.id
+	collection_url = "https://dev.azure.com/example"
+	root_path = "$/Billing/Release"
+	username = "EXAMPLE\\builder"
+	password = "secret"
+
+	workspace {
+		location = "local"
+		name_prefix = "tc-billing"
+	}
+}
+`
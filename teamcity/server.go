@@ -0,0 +1,111 @@
+package teamcity
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+
+	api "github.com/leidruid/go-teamcity/teamcity"
+)
+
+// minimumServerVersion is the oldest TeamCity version the provider is tested against
+const minimumServerVersion = "2019.2.2"
+
+var serverVersionRegexp = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// serverVersion is a TeamCity release version, e.g. 2020.1.3
+type serverVersion struct {
+	major, minor, patch int
+}
+
+// parseServerVersion reads the version reported by TeamCity, e.g. "2020.1.3 (build 78938)" or "2020.2 EAP1 (build 84537)"
+func parseServerVersion(v string) (serverVersion, error) {
+	m := serverVersionRegexp.FindStringSubmatch(v)
+	if m == nil {
+		return serverVersion{}, fmt.Errorf("unrecognized TeamCity version '%s'", v)
+	}
+	var out serverVersion
+	out.major, _ = strconv.Atoi(m[1])
+	out.minor, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		out.patch, _ = strconv.Atoi(m[3])
+	}
+	return out, nil
+}
+
+func (v serverVersion) atLeast(o serverVersion) bool {
+	if v.major != o.major {
+		return v.major > o.major
+	}
+	if v.minor != o.minor {
+		return v.minor > o.minor
+	}
+	return v.patch >= o.patch
+}
+
+func (v serverVersion) String() string {
+	if v.patch == 0 {
+		return fmt.Sprintf("%d.%d", v.major, v.minor)
+	}
+	return fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+}
+
+// serverInfo describes the TeamCity server the provider is connected to, as detected at configure time
+type serverInfo struct {
+	Version     string
+	BuildNumber string
+	WebURL      string
+
+	// version is nil when the reported version could not be parsed, in which case attributes are not gated.
+	// The whole serverInfo is nil when the server could not be queried.
+	version *serverVersion
+}
+
+// detectServer queries the server information and stores it in the client.
+// The provider keeps working without it, e.g. when the credentials may not read /app/rest/server, leaving attributes ungated.
+func (c *Client) detectServer() error {
+	var dt api.Server
+	if err := c.doRequest("GET", "server", nil, &dt, "server"); err != nil {
+		return fmt.Errorf("error detecting TeamCity server version: %s", err)
+	}
+
+	c.server = &serverInfo{
+		Version:     dt.Version,
+		BuildNumber: dt.BuildNumber,
+		WebURL:      dt.WebURL,
+	}
+
+	v, err := parseServerVersion(dt.Version)
+	if err != nil {
+		log.Printf("[WARN] %s, version-specific attributes will not be checked", err)
+		return nil
+	}
+	c.server.version = &v
+
+	if min, _ := parseServerVersion(minimumServerVersion); !v.atLeast(min) {
+		log.Printf("[WARN] TeamCity %s is older than %s, the oldest version supported by this provider", v, min)
+	}
+	return nil
+}
+
+// checkServerVersion fails when the server is older than min, describing what needs it with feature.
+// Nothing is checked when the server version is unknown.
+func checkServerVersion(meta interface{}, min serverVersion, feature string) error {
+	client, ok := meta.(*Client)
+	if !ok || client.server == nil || client.server.version == nil {
+		return nil
+	}
+	if client.server.version.atLeast(min) {
+		return nil
+	}
+	return fmt.Errorf("%s requires TeamCity %s or newer, but the server runs %s", feature, min, client.server.version)
+}
+
+func mustParseServerVersion(v string) serverVersion {
+	out, err := parseServerVersion(v)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
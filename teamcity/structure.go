@@ -32,6 +32,14 @@ func expandStringSlice(configured []interface{}) []string {
 	return vs
 }
 
+func expandStringMap(configured map[string]interface{}) map[string]string {
+	vs := make(map[string]string, len(configured))
+	for k, v := range configured {
+		vs[k] = v.(string)
+	}
+	return vs
+}
+
 // Takes list of pointers to strings. Expand to an array
 // of raw strings and returns a []interface{}
 // to keep compatibility w/ schema.NewSet schema.NewSet
@@ -0,0 +1,245 @@
+package teamcity
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"log"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// newBaseTransport returns a transport with the same settings as http.DefaultTransport.
+// go-teamcity replaces http.DefaultTransport with a logging wrapper when imported, so it cannot be cloned.
+func newBaseTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// contextTransport sends every request with a fixed context, for go-teamcity calls that do not accept one
+type contextTransport struct {
+	base http.RoundTripper
+	ctx  context.Context
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req.WithContext(t.ctx))
+}
+
+// headerTransport adds a fixed set of headers to every request
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the request they were given
+	out := req.Clone(req.Context())
+	for k, v := range t.headers {
+		out.Header.Set(k, v)
+	}
+	return t.base.RoundTrip(out)
+}
+
+// semaphoreTransport limits the number of requests in flight. A slot is held until the response body is closed.
+type semaphoreTransport struct {
+	base  http.RoundTripper
+	slots chan struct{}
+}
+
+func newSemaphoreTransport(base http.RoundTripper, max int) *semaphoreTransport {
+	return &semaphoreTransport{
+		base:  base,
+		slots: make(chan struct{}, max),
+	}
+}
+
+func (t *semaphoreTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.slots <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		t.release()
+		return resp, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: t.release}
+	return resp, nil
+}
+
+func (t *semaphoreTransport) release() {
+	<-t.slots
+}
+
+// releasingBody calls release once, when the body is closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// retryTransport retries requests that failed with a transient error, waiting with exponential backoff and jitter between attempts.
+// Rate limited (429) and unavailable (503) responses are retried for every method, since the server rejected them before doing any work.
+// Other 5xx responses and connection errors are only retried for idempotent methods.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	waitMin    time.Duration
+	waitMax    time.Duration
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func newRetryTransport(base http.RoundTripper, maxRetries int, waitMin, waitMax time.Duration) *retryTransport {
+	return &retryTransport{
+		base:       base,
+		maxRetries: maxRetries,
+		waitMin:    waitMin,
+		waitMax:    waitMax,
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+
+		if attempt >= t.maxRetries || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			// The body has been consumed and cannot be replayed
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		if err != nil {
+			log.Printf("[DEBUG] %s %s failed: %s. Retrying in %s (%d/%d)", req.Method, req.URL, err, wait, attempt+1, t.maxRetries)
+		} else {
+			log.Printf("[DEBUG] %s %s returned '%s'. Retrying in %s (%d/%d)", req.Method, req.URL, resp.Status, wait, attempt+1, t.maxRetries)
+			drainBody(resp.Body)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = cloneRequestWithBody(req, body)
+		}
+	}
+}
+
+// backoff returns how long to wait before the next attempt. A Retry-After header sent by the server takes precedence over the computed backoff, but is capped at waitMax.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if wait > t.waitMax {
+				return t.waitMax
+			}
+			return wait
+		}
+	}
+
+	wait := float64(t.waitMin) * math.Pow(2, float64(attempt))
+	if wait > float64(t.waitMax) {
+		wait = float64(t.waitMax)
+	}
+
+	// Equal jitter: wait at least half of the backoff, plus a random part of the other half
+	half := int64(wait / 2)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return time.Duration(half + t.rand.Int63n(half+1))
+}
+
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		if req.Context().Err() != nil {
+			return false
+		}
+		return isIdempotent(req.Method)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable:
+		return true
+	case resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented:
+		return isIdempotent(req.Method)
+	}
+	return false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter reads a Retry-After header value, which is either a number of seconds or an HTTP date
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(v); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+func cloneRequestWithBody(req *http.Request, body io.ReadCloser) *http.Request {
+	out := req.Clone(req.Context())
+	out.Body = body
+	return out
+}
+
+func drainBody(body io.ReadCloser) {
+	// Drain a bounded amount so the connection can be reused
+	io.Copy(ioutil.Discard, io.LimitReader(body, 4096))
+	body.Close()
+}
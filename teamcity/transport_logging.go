@@ -0,0 +1,169 @@
+package teamcity
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/logging"
+)
+
+const redacted = "<redacted>"
+
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// sensitiveNameFragments identify properties and parameters holding secrets, such as VCS root 'secure:password'
+// or commit status publisher 'secure:github_access_token'
+var sensitiveNameFragments = []string{"secure:", "password", "passphrase", "token", "secret"}
+
+// sensitivePathFragments identify requests reading or writing a single parameter or property value as plain text,
+// such as PUT /app/rest/projects/id:X/parameters/env.TOKEN/value, and uploads of SSH private keys
+var sensitivePathFragments = []string{"/parameters/", "/properties/", "secure:", "/sshKeys"}
+
+// loggingTransport writes every REST call to the Terraform log: a summary at DEBUG level, and headers and bodies at TRACE level.
+// Credentials in headers and values of password-typed properties and parameters are redacted.
+type loggingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	trace := logging.LogLevel() == "TRACE"
+
+	if trace {
+		var body []byte
+		if req.GetBody != nil {
+			if rc, err := req.GetBody(); err == nil {
+				body, _ = ioutil.ReadAll(rc)
+				rc.Close()
+			}
+		}
+		log.Printf("[TRACE] TeamCity API request: %s %s\n%s%s", req.Method, req.URL, formatHeaders(req.Header), redactBody(req.URL.Path, body))
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	latency := time.Since(start).Round(time.Millisecond)
+
+	if err != nil {
+		log.Printf("[DEBUG] TeamCity API: %s %s failed after %s: %s", req.Method, req.URL, latency, err)
+		return resp, err
+	}
+	log.Printf("[DEBUG] TeamCity API: %s %s -> %s (%s)", req.Method, req.URL, resp.Status, latency)
+
+	if trace {
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("[TRACE] TeamCity API response: %s %s -> %s\n%s%s", req.Method, req.URL, resp.Status, formatHeaders(resp.Header), redactBody(req.URL.Path, body))
+	}
+
+	return resp, nil
+}
+
+func formatHeaders(h http.Header) string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf strings.Builder
+	for _, k := range keys {
+		v := strings.Join(h[k], ", ")
+		for _, s := range sensitiveHeaders {
+			if http.CanonicalHeaderKey(k) == s {
+				v = redacted
+			}
+		}
+		buf.WriteString(k + ": " + v + "\n")
+	}
+	return buf.String()
+}
+
+// redactBody returns the body to be logged, with secrets removed from JSON payloads.
+// Plain text bodies of parameter and property values carry no name to decide on, so only their length is logged.
+func redactBody(path string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		switch payload.(type) {
+		case map[string]interface{}, []interface{}:
+			if out, err := json.MarshalIndent(redactValue(payload), "", "  "); err == nil {
+				return string(out)
+			}
+		}
+	}
+
+	// Plain text, including values such as '12345' that also decode as JSON
+	if isSensitivePath(path) {
+		return fmt.Sprintf("%s (%d bytes)", redacted, len(body))
+	}
+	return string(body)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		// Properties and parameters: {"name": "secure:password", "value": "...", "type": {"rawValue": "password"}}
+		if _, ok := t["value"]; ok && isSensitiveProperty(t) {
+			t["value"] = redacted
+		}
+		for k, e := range t {
+			if _, isString := e.(string); isString && k != "name" && isSensitiveName(k) {
+				t[k] = redacted
+				continue
+			}
+			t[k] = redactValue(e)
+		}
+		return t
+	case []interface{}:
+		for i, e := range t {
+			t[i] = redactValue(e)
+		}
+		return t
+	}
+	return v
+}
+
+func isSensitiveProperty(p map[string]interface{}) bool {
+	if name, ok := p["name"].(string); ok && isSensitiveName(name) {
+		return true
+	}
+	if pt, ok := p["type"].(map[string]interface{}); ok {
+		if raw, ok := pt["rawValue"].(string); ok && strings.HasPrefix(raw, "password") {
+			return true
+		}
+	}
+	return false
+}
+
+func isSensitivePath(path string) bool {
+	for _, f := range sensitivePathFragments {
+		if strings.Contains(path, f) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSensitiveName(name string) bool {
+	name = strings.ToLower(name)
+	for _, f := range sensitiveNameFragments {
+		if strings.Contains(name, f) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package teamcity_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	api "github.com/leidruid/go-teamcity/teamcity"
+	"github.com/leidruid/terraform-provider-teamcity/teamcity"
+)
+
+func captureTraceLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	level, set := os.LookupEnv("TF_LOG")
+	os.Setenv("TF_LOG", "TRACE")
+	t.Cleanup(func() {
+		log.SetOutput(os.Stderr)
+		if set {
+			os.Setenv("TF_LOG", level)
+		} else {
+			os.Unsetenv("TF_LOG")
+		}
+	})
+	return &buf
+}
+
+func TestLoggingTransport_RedactsSecrets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"Vcs1","name":"vcs","vcsName":"jetbrains.git","project":{"id":"_Root"},"properties":{"count":2,"property":[` +
+			`{"name":"url","value":"https://github.com/leidruid/go-teamcity"},` +
+			`{"name":"secure:password","value":"server-side-secret"}]}}`))
+	}))
+	defer srv.Close()
+
+	buf := captureTraceLog(t)
+
+	config := teamcity.Config{Address: srv.URL, AccessToken: "very-secret-token"}
+	client, err := config.Client()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	opts, _ := api.NewGitVcsRootOptions("refs/heads/master", "https://github.com/leidruid/go-teamcity", "", api.GitAuthMethodPassword, "admin", "client-side-secret")
+	vcs, _ := api.NewGitVcsRoot("_Root", "vcs", opts)
+	client.VcsRoots.Create("_Root", vcs)
+	client.VcsRoots.GetByID("Vcs1")
+
+	out := buf.String()
+	for _, secret := range []string{"very-secret-token", "client-side-secret", "server-side-secret"} {
+		if strings.Contains(out, secret) {
+			t.Fatalf("secret %q leaked into the log:\n%s", secret, out)
+		}
+	}
+	for _, expected := range []string{"[DEBUG] TeamCity API: POST", "[DEBUG] TeamCity API: GET", "200 OK", "Authorization: <redacted>", "https://github.com/leidruid/go-teamcity"} {
+		if !strings.Contains(out, expected) {
+			t.Fatalf("expected log to contain %q:\n%s", expected, out)
+		}
+	}
+}
+
+func TestLoggingTransport_RedactsPasswordTypedParameters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"Project1","name":"Project 1","parameters":{"count":2,"property":[` +
+			`{"name":"env.PUBLIC","value":"visible"},` +
+			`{"name":"env.DEPLOY_KEY","value":"hidden-value","type":{"rawValue":"password display='hidden'"}}]}}`))
+	}))
+	defer srv.Close()
+
+	buf := captureTraceLog(t)
+
+	config := teamcity.Config{Address: srv.URL, Username: "admin", Password: "admin-password"}
+	client, err := config.Client()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := client.Projects.GetByID("Project1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "hidden-value") {
+		t.Fatalf("password parameter leaked into the log:\n%s", out)
+	}
+	if !strings.Contains(out, "visible") {
+		t.Fatalf("expected regular parameter to be logged:\n%s", out)
+	}
+}
+
+func TestLoggingTransport_RedactsPlainTextParameterValues(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write(body)
+	}))
+	defer srv.Close()
+
+	buf := captureTraceLog(t)
+
+	config := teamcity.Config{Address: srv.URL, Username: "admin", Password: "admin-password"}
+	client, err := config.Client()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// The second value is a valid JSON number
+	for _, value := range []string{"plain-text-secret", "80412935"} {
+		if err := client.SetProjectParameterValue("Project1", "env.DEPLOY_KEY", value); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	out := buf.String()
+	for _, secret := range []string{"plain-text-secret", "80412935"} {
+		if strings.Contains(out, secret) {
+			t.Fatalf("parameter value %q leaked into the log:\n%s", secret, out)
+		}
+	}
+	if !strings.Contains(out, "<redacted> (17 bytes)") {
+		t.Fatalf("expected the length of the redacted value to be logged:\n%s", out)
+	}
+}
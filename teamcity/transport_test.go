@@ -0,0 +1,196 @@
+package teamcity_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	api "github.com/leidruid/go-teamcity/teamcity"
+	"github.com/leidruid/terraform-provider-teamcity/teamcity"
+)
+
+// failingServer fails the first 'failures' requests with the given status, then answers with a valid project
+func failingServer(failures int32, status int, header http.Header) (*httptest.Server, *int32) {
+	var count int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&count, 1)
+		if n <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"Project1","name":"Project 1"}`))
+	}))
+	return srv, &count
+}
+
+func testRetryClient(t *testing.T, address string, maxRetries int) *teamcity.Client {
+	config := teamcity.Config{
+		Address:      address,
+		AccessToken:  "token",
+		MaxRetries:   maxRetries,
+		RetryWaitMin: time.Millisecond,
+		RetryWaitMax: 10 * time.Millisecond,
+	}
+	client, err := config.Client()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return client
+}
+
+func TestRetryTransport_RetriesServerErrors(t *testing.T) {
+	srv, count := failingServer(2, http.StatusBadGateway, nil)
+	defer srv.Close()
+
+	client := testRetryClient(t, srv.URL, 3)
+	if _, err := client.Projects.GetByID("Project1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if *count != 3 {
+		t.Fatalf("expected 3 requests, got %d", *count)
+	}
+}
+
+func TestRetryTransport_GivesUpAfterMaxRetries(t *testing.T) {
+	srv, count := failingServer(10, http.StatusInternalServerError, nil)
+	defer srv.Close()
+
+	client := testRetryClient(t, srv.URL, 2)
+	if _, err := client.Projects.GetByID("Project1"); err == nil {
+		t.Fatalf("expected error after exhausting retries")
+	}
+	if *count != 3 {
+		t.Fatalf("expected 3 requests, got %d", *count)
+	}
+}
+
+func TestRetryTransport_DisabledWithZeroRetries(t *testing.T) {
+	srv, count := failingServer(1, http.StatusBadGateway, nil)
+	defer srv.Close()
+
+	client := testRetryClient(t, srv.URL, 0)
+	if _, err := client.Projects.GetByID("Project1"); err == nil {
+		t.Fatalf("expected error with retries disabled")
+	}
+	if *count != 1 {
+		t.Fatalf("expected 1 request, got %d", *count)
+	}
+}
+
+func TestRetryTransport_DoesNotRetryNonIdempotentServerErrors(t *testing.T) {
+	srv, count := failingServer(1, http.StatusInternalServerError, nil)
+	defer srv.Close()
+
+	client := testRetryClient(t, srv.URL, 3)
+	project, _ := api.NewProject("Project 1", "", "")
+	if _, err := client.Projects.Create(project); err == nil {
+		t.Fatalf("expected POST to fail without retrying")
+	}
+	if *count != 1 {
+		t.Fatalf("expected 1 request, got %d", *count)
+	}
+}
+
+func TestRetryTransport_RetriesTooManyRequestsHonouringRetryAfter(t *testing.T) {
+	srv, count := failingServer(1, http.StatusTooManyRequests, http.Header{"Retry-After": []string{"1"}})
+	defer srv.Close()
+
+	config := teamcity.Config{
+		Address:      srv.URL,
+		AccessToken:  "token",
+		MaxRetries:   1,
+		RetryWaitMin: time.Millisecond,
+		RetryWaitMax: 5 * time.Second,
+	}
+	client, err := config.Client()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	start := time.Now()
+	if _, err := client.Projects.GetByID("Project1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("expected Retry-After to be honoured, retried after %s", elapsed)
+	}
+	if *count != 2 {
+		t.Fatalf("expected 2 requests, got %d", *count)
+	}
+}
+
+func TestRetryTransport_ReplaysRequestBody(t *testing.T) {
+	var count int32
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(buf))
+		if atomic.AddInt32(&count, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write(buf)
+	}))
+	defer srv.Close()
+
+	client := testRetryClient(t, srv.URL, 3)
+	project := &api.Project{ID: "Project1", Name: "Renamed", Parameters: api.NewParametersEmpty()}
+	// The server echoes every body back, so only the first two calls (name update and its retry) matter here
+	client.Projects.Update(project)
+	if len(bodies) < 2 || bodies[0] != "Renamed" || bodies[1] != "Renamed" {
+		t.Fatalf("expected body to be replayed on retry, got %q", bodies)
+	}
+}
+
+func TestSemaphoreTransport_LimitsConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"Project1","name":"Project 1"}`))
+	}))
+	defer srv.Close()
+
+	config := teamcity.Config{
+		Address:               srv.URL,
+		AccessToken:           "token",
+		MaxConcurrentRequests: 2,
+	}
+	client, err := config.Client()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Projects.GetByID("Project1"); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight != 2 {
+		t.Fatalf("expected at most 2 concurrent requests, got %d", maxInFlight)
+	}
+}
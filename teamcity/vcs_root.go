@@ -0,0 +1,139 @@
+package teamcity
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	api "github.com/leidruid/go-teamcity/teamcity"
+)
+
+// vcsRoot is the REST representation of a VCS Root of any type.
+// go-teamcity only models Git VCS Roots, so the other VCS types are managed with it through Client.doRequest.
+type vcsRoot struct {
+	ID                        string                `json:"id,omitempty"`
+	Name                      string                `json:"name,omitempty"`
+	VcsName                   string                `json:"vcsName,omitempty"`
+	ModificationCheckInterval int                   `json:"modificationCheckInterval,omitempty"`
+	Project                   *api.ProjectReference `json:"project,omitempty"`
+	Properties                *api.Properties       `json:"properties,omitempty"`
+}
+
+// newVcsRoot builds a VCS Root of the given type from the arguments shared by all VCS Root resources
+func newVcsRoot(d *schema.ResourceData, vcsName string, props *api.Properties) *vcsRoot {
+	return &vcsRoot{
+		Name:                      d.Get("name").(string),
+		VcsName:                   vcsName,
+		ModificationCheckInterval: d.Get("modification_check_interval").(int),
+		Project:                   &api.ProjectReference{ID: d.Get("project_id").(string)},
+		Properties:                props,
+	}
+}
+
+func (c *Client) createVcsRoot(dt *vcsRoot) (*vcsRoot, error) {
+	var out vcsRoot
+	if err := c.doRequest("POST", "vcs-roots", dt, &out, "VcsRoot"); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) getVcsRoot(id string) (*vcsRoot, error) {
+	var out vcsRoot
+	if err := c.doRequest("GET", vcsRootPath(id), nil, &out, "VcsRoot"); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// updateVcsRoot changes the VCS Root in-place. TeamCity does not support PUT on the whole VCS Root,
+// so properties and each changed field are sent separately, like go-teamcity does for Git VCS Roots.
+func (c *Client) updateVcsRoot(d *schema.ResourceData, dt *vcsRoot) error {
+	path := vcsRootPath(d.Id())
+
+	if err := c.doRequest("PUT", path+"/properties", dt.Properties, nil, "VcsRoot properties"); err != nil {
+		return err
+	}
+	if d.HasChange("name") {
+		if err := c.doRequest("PUT", path+"/name", dt.Name, nil, "VcsRoot name field"); err != nil {
+			return fmt.Errorf("error when updating 'name' field for VcsRoot. Resource may be in partial update state. %s", err)
+		}
+	}
+	if d.HasChange("project_id") {
+		if err := c.doRequest("PUT", path+"/projectId", dt.Project.ID, nil, "VcsRoot projectId field"); err != nil {
+			return fmt.Errorf("error when updating 'projectId' field for VcsRoot. Resource may be in partial update state. %s", err)
+		}
+	}
+	if d.HasChange("modification_check_interval") && dt.ModificationCheckInterval > 0 {
+		v := strconv.Itoa(dt.ModificationCheckInterval)
+		if err := c.doRequest("PUT", path+"/modificationCheckInterval", v, nil, "VcsRoot modificationCheckInterval field"); err != nil {
+			return fmt.Errorf("error when updating 'modificationCheckInterval' field for VcsRoot. Resource may be in partial update state. %s", err)
+		}
+	}
+	return nil
+}
+
+// readVcsRoot retrieves the VCS Root and sets the arguments shared by all VCS Root resources.
+// It returns nil, nil when the VCS Root no longer exists and was removed from state.
+func readVcsRoot(d *schema.ResourceData, client *Client, vcsName string) (*vcsRoot, error) {
+	dt, err := client.getVcsRoot(d.Id())
+	if err != nil {
+		return nil, handleNotFound(d, client, vcsRootPath(d.Id()), err)
+	}
+	if dt.VcsName != vcsName {
+		return nil, fmt.Errorf("VCS with ID = %s has a type mismatch, expected '%s'. Actual type: %s", d.Id(), vcsName, dt.VcsName)
+	}
+
+	if err := d.Set("name", dt.Name); err != nil {
+		return nil, err
+	}
+	if dt.Project != nil {
+		if err := d.Set("project_id", dt.Project.ID); err != nil {
+			return nil, err
+		}
+	}
+	if dt.ModificationCheckInterval > 0 {
+		if err := d.Set("modification_check_interval", dt.ModificationCheckInterval); err != nil {
+			return nil, err
+		}
+	}
+	if dt.Properties == nil {
+		dt.Properties = api.NewPropertiesEmpty()
+	}
+	return dt, nil
+}
+
+func resourceVcsRootDelete(client *Client, id string) error {
+	log.Printf("[DEBUG]: Destroying vcs root %v", id)
+	if err := client.doRequest("DELETE", vcsRootPath(id), nil, nil, "VcsRoot"); err != nil {
+		return err
+	}
+	log.Printf("[INFO]: Destroyed vcs root %v", id)
+	return nil
+}
+
+// setProperty adds the property when the value is not empty, since TeamCity treats missing properties as their default
+func setProperty(props *api.Properties, name string, value string) {
+	if value != "" {
+		props.AddOrReplaceValue(name, value)
+	}
+}
+
+// boolProperty reads a boolean property, returning def when it is not set
+func boolProperty(props map[string]string, name string, def bool) bool {
+	v, err := strconv.ParseBool(props[name])
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// splitLines reads a multi-line property into a list, such as rules entered one per line in the UI
+func splitLines(v string) []interface{} {
+	if v == "" {
+		return nil
+	}
+	return flattenStringSlice(strings.Split(v, "\n"))
+}